@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateADRSnapshot renders the dependencies of the focus plugins (given
+// by composer or folder name) as a Markdown section for an architecture
+// decision record: a Mermaid block followed by a bullet list of every edge
+// into or out of a focus plugin. The output contains no timestamps and is
+// sorted throughout, so regenerating it only changes when the graph does.
+func (pa *PluginAnalyzer) GenerateADRSnapshot(focus []string) (string, error) {
+	focused := make(map[string]bool)
+	var folders []string
+	for _, name := range focus {
+		plugin := pa.FindPlugin(name)
+		if plugin == nil {
+			return "", fmt.Errorf("unknown plugin %q", name)
+		}
+		if !focused[plugin.Name] {
+			focused[plugin.Name] = true
+			folders = append(folders, plugin.FolderName)
+		}
+	}
+	sort.Strings(folders)
+
+	type adrEdge struct {
+		from, to *Plugin
+		dep      Dependency
+	}
+	var edges []adrEdge
+	for _, plugin := range pa.Plugins {
+		if plugin.IsExternal {
+			continue
+		}
+		for _, dep := range plugin.Dependencies {
+			target := pa.Plugins[dep.Name]
+			if target.IsExternal && !pa.ShowExternalDeps {
+				continue
+			}
+			if focused[plugin.Name] || focused[dep.Name] {
+				edges = append(edges, adrEdge{from: plugin, to: target, dep: dep})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		a, b := edges[i], edges[j]
+		if a.from.FolderName != b.from.FolderName {
+			return a.from.FolderName < b.from.FolderName
+		}
+		if a.to.FolderName != b.to.FolderName {
+			return a.to.FolderName < b.to.FolderName
+		}
+		return kindOrder[a.dep.Kind] < kindOrder[b.dep.Kind]
+	})
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("## Dependency snapshot: %s\n\n", strings.Join(folders, ", ")))
+	sb.WriteString("```mermaid\ngraph TD\n")
+	for _, folder := range folders {
+		// Focus plugins without any edge still appear in the diagram.
+		sb.WriteString(fmt.Sprintf("    \"%s\"\n", folder))
+	}
+	for _, e := range edges {
+		arrow := edgeGroup{Target: e.dep.Name, Kinds: []DependencyKind{e.dep.Kind}}.mermaidArrow()
+		sb.WriteString(fmt.Sprintf("    \"%s\" %s \"%s\"\n", e.from.FolderName, arrow, e.to.FolderName))
+	}
+	sb.WriteString("```\n\n")
+
+	sb.WriteString("Affected edges:\n\n")
+	if len(edges) == 0 {
+		sb.WriteString("- none\n")
+	}
+	for _, e := range edges {
+		detail := string(e.dep.Kind)
+		if e.dep.Constraint != "" {
+			detail += " " + e.dep.Constraint
+		}
+		target := e.to.FolderName
+		if e.to.IsExternal {
+			target += " (external)"
+		}
+		sb.WriteString(fmt.Sprintf("- %s → %s (`%s`)\n", e.from.FolderName, target, detail))
+	}
+
+	return sb.String(), nil
+}
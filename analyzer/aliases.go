@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// normalizePackageName lowercases a package name and strips surrounding
+// whitespace. Composer package names are case-insensitive, so
+// "Shopware/Core " in a hand-edited require section means shopware/core.
+func normalizePackageName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// unnamedPackageName returns the name a plugin whose composer.json has no
+// name is keyed by: its folder behind a prefix no composer name can contain,
+// so nameless plugins neither replace each other nor a real package.
+func unnamedPackageName(folder string) string {
+	return "folder:" + filepath.ToSlash(folder)
+}
+
+// normalizeRequirements returns a require section keyed by normalized
+// package names.
+func normalizeRequirements(section map[string]string) map[string]string {
+	if section == nil {
+		return nil
+	}
+	normalized := make(map[string]string, len(section))
+	for name, constraint := range section {
+		normalized[normalizePackageName(name)] = constraint
+	}
+	return normalized
+}
+
+// ParseAliasGroups turns "alias=canonical" entries into a lookup map from
+// alias to canonical package name. Both names are normalized.
+func ParseAliasGroups(entries []string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for _, entry := range entries {
+		alias, canonical, ok := strings.Cut(entry, "=")
+		alias, canonical = normalizePackageName(alias), normalizePackageName(canonical)
+		if !ok || alias == "" || canonical == "" {
+			return nil, fmt.Errorf("invalid alias group %q: expected alias=canonical", entry)
+		}
+		aliases[alias] = canonical
+	}
+	return aliases, nil
+}
+
+// canonicalName resolves a required package name through the configured
+// alias groups and then through the replace and provide sections of
+// internal plugins, unless a scanned plugin carries the name itself. A
+// virtual package provided by several plugins resolves to the first one
+// scanned; see AmbiguousProviders.
+func (pa *PluginAnalyzer) canonicalName(name string) string {
+	if canonical, ok := pa.Aliases[name]; ok {
+		name = canonical
+	}
+	if plugin, ok := pa.Plugins[name]; ok && !plugin.IsExternal {
+		return name
+	}
+	if replacing, ok := pa.replacedBy[name]; ok {
+		return replacing
+	}
+	if providers := pa.providedBy[name]; len(providers) > 0 {
+		return providers[0]
+	}
+	return name
+}
+
+// AmbiguousProvider is a virtual package several internal plugins provide.
+type AmbiguousProvider struct {
+	Package   string
+	Providers []string // folders of the providing plugins, the one edges go to first
+}
+
+// AmbiguousProviders returns the virtual packages provided by more than one
+// internal plugin, sorted by name. Requirements on them are drawn to the
+// first provider scanned.
+func (pa *PluginAnalyzer) AmbiguousProviders() []AmbiguousProvider {
+	var ambiguous []AmbiguousProvider
+	for pkg, providers := range pa.providedBy {
+		if len(providers) < 2 {
+			continue
+		}
+		folders := make([]string, len(providers))
+		for i, name := range providers {
+			folders[i] = name
+			if plugin, ok := pa.Plugins[name]; ok {
+				folders[i] = plugin.FolderName
+			}
+		}
+		ambiguous = append(ambiguous, AmbiguousProvider{Package: pkg, Providers: folders})
+	}
+	sort.Slice(ambiguous, func(i, j int) bool { return ambiguous[i].Package < ambiguous[j].Package })
+	return ambiguous
+}
+
+// hasDependency reports whether plugin already has an edge of the given
+// kind to dep.
+func hasDependency(plugin *Plugin, dep string, kind DependencyKind) bool {
+	for _, d := range plugin.Dependencies {
+		if d.Name == dep && d.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// countExternalUse records that plugin uses the external package dep.
+// A plugin is counted once per package, however many of its requirements
+// resolve to it.
+func (pa *PluginAnalyzer) countExternalUse(dep string, plugin *Plugin) {
+	if pa.externalUsers == nil {
+		pa.externalUsers = make(map[string]map[string]bool)
+	}
+	if pa.externalUsers[dep] == nil {
+		pa.externalUsers[dep] = make(map[string]bool)
+	}
+	pa.externalUsers[dep][plugin.Name] = true
+	pa.ExternalDepsCount[dep] = len(pa.externalUsers[dep])
+}
@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAliasGroupsNormalizes(t *testing.T) {
+	aliases, err := ParseAliasGroups([]string{" Shopware/Platform = shopware/CORE "})
+	if err != nil {
+		t.Fatalf("ParseAliasGroups: %v", err)
+	}
+	if want := map[string]string{"shopware/platform": "shopware/core"}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("ParseAliasGroups() = %v, want %v", aliases, want)
+	}
+}
+
+func TestRequireKeysIgnoreCaseAndWhitespace(t *testing.T) {
+	aliases, err := ParseAliasGroups([]string{"shopware/platform=shopware/core"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "Vendor/A", "require": {" Shopware/Platform": "*"}}`,
+		"B": `{"name": "vendor/b", "require": {"SHOPWARE/CORE ": "*", " VENDOR/a ": "*"}}`,
+	}, showExternal, func(pa *PluginAnalyzer) {
+		pa.Aliases = aliases
+	})
+
+	if _, ok := pa.Plugins["vendor/a"]; !ok {
+		t.Fatalf("plugin not keyed by its normalized name: %v", pa.InternalPluginNames())
+	}
+	for name, want := range map[string][]string{
+		"vendor/a": {"shopware/core"},
+		"vendor/b": {"shopware/core", "vendor/a"},
+	} {
+		var got []string
+		for _, dep := range pa.Plugins[name].Dependencies {
+			got = append(got, dep.Name)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("dependencies of %s = %q, want %q", name, got, want)
+		}
+	}
+	if plugin := pa.Plugins["shopware/core"]; plugin == nil || !plugin.IsExternal {
+		t.Errorf("shopware/core is not an external node")
+	}
+	if _, ok := pa.Plugins["shopware/platform"]; ok {
+		t.Errorf("alias shopware/platform has a node of its own")
+	}
+	if got := pa.ExternalDepsCount["shopware/core"]; got != 2 {
+		t.Errorf("ExternalDepsCount[shopware/core] = %d, want 2", got)
+	}
+}
+
+func TestNamelessPluginsDontOverwriteEachOther(t *testing.T) {
+	pa, logs := scanFixture(t, map[string]string{
+		"First":  `{"type": "shopware-platform-plugin", "require": {"v/named": "*"}}`,
+		"Second": `{"type": "shopware-platform-plugin"}`,
+		"Named":  `{"name": "v/named"}`,
+	})
+	want := []string{"folder:First", "folder:Second", "v/named"}
+	if got := pa.InternalPluginNames(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("InternalPluginNames() = %q, want %q", got, want)
+	}
+	if _, ok := pa.Plugins[""]; ok {
+		t.Error("a plugin is keyed by the empty name")
+	}
+	if folder := pa.Plugins["folder:Second"].FolderName; folder != "Second" {
+		t.Errorf("folder:Second has FolderName %q", folder)
+	}
+	if deps := pa.Plugins["folder:First"].Dependencies; len(deps) != 1 || deps[0].Name != "v/named" {
+		t.Errorf("dependencies of First = %+v", deps)
+	}
+	if !strings.Contains(logs.String(), "has no name, using folder:First") {
+		t.Errorf("no warning about the missing name:\n%s", logs)
+	}
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// DisallowedExternal is an external package that is not on the allow-list.
+type DisallowedExternal struct {
+	Package string
+	Users   []string // folders of the plugins requiring it, sorted
+}
+
+// LoadAllowlist reads an allow-list file in the denylist format: one
+// permitted package name or glob pattern such as "symfony/*" per line.
+func LoadAllowlist(filename string) ([]string, error) {
+	return loadPackagePatterns(filename, "allow-list")
+}
+
+// allowed reports whether an external package matches AllowedExternals.
+func (pa *PluginAnalyzer) allowed(pkg string) bool {
+	pkg = strings.ToLower(pkg)
+	for _, pattern := range pa.AllowedExternals {
+		if ok, _ := path.Match(pattern, pkg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DisallowedExternals returns the external packages required (or, with
+// IncludeDev, required for development) by internal plugins that match none
+// of AllowedExternals, sorted by name. External packages count whether or
+// not they are drawn with ShowExternalDeps; platform packages such as php
+// and ext-json are never checked.
+func (pa *PluginAnalyzer) DisallowedExternals() []DisallowedExternal {
+	var disallowed []DisallowedExternal
+	for pkg, users := range pa.externalUsers {
+		if isPlatformPackage(pkg) || pa.allowed(pkg) {
+			continue
+		}
+		var folders []string
+		for user := range users {
+			if plugin, ok := pa.Plugins[user]; ok {
+				user = plugin.FolderName
+			}
+			folders = append(folders, user)
+		}
+		sort.Strings(folders)
+		disallowed = append(disallowed, DisallowedExternal{Package: pkg, Users: folders})
+	}
+	sort.Slice(disallowed, func(i, j int) bool { return disallowed[i].Package < disallowed[j].Package })
+	return disallowed
+}
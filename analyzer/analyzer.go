@@ -0,0 +1,1000 @@
+// Package analyzer scans Shopware 6 plugin folders and analyzes the
+// dependencies declared in their composer.json files. It powers the
+// sw6-plugin-analyzer command and can be embedded in other programs:
+// results are returned as values, diagrams as strings or files, and only
+// warnings are logged, as selected by LogLevel, to the analyzer's Logger.
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ComposerJSON struct {
+	Name       string            `json:"name"`
+	Version    string            `json:"version"`
+	Type       string            `json:"type"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+	Suggest    map[string]string `json:"suggest"`
+	Replace    map[string]string `json:"replace"`
+	Provide    map[string]string `json:"provide"`
+	Conflict   map[string]string `json:"conflict"`
+	Extra      ComposerExtra     `json:"extra"`
+	Keywords   []string          `json:"keywords"`
+	Autoload   ComposerAutoload  `json:"autoload"`
+}
+
+// ComposerAutoload holds the autoload mappings of a composer.json. Only the
+// PSR-4 namespace prefixes are used, so the paths are left unparsed.
+type ComposerAutoload struct {
+	PSR4 map[string]json.RawMessage `json:"psr-4"`
+}
+
+// ComposerExtra holds the fields of the composer.json "extra" section the
+// Analyzer uses.
+type ComposerExtra struct {
+	ShopwarePluginClass string          `json:"shopware-plugin-class"`
+	Label               json.RawMessage `json:"label"`            // per-locale labels, see composerLabel
+	ShopwareBundles     json.RawMessage `json:"shopware-bundles"` // bundled plugins, see shopwareBundles
+}
+
+// DependencyKind identifies the composer.json section a dependency was declared in.
+type DependencyKind string
+
+const (
+	KindRequire    DependencyKind = "require"
+	KindRequireDev DependencyKind = "require-dev"
+	KindSuggest    DependencyKind = "suggest"
+	KindBundle     DependencyKind = "bundle" // extra.shopware-bundles, with IncludeBundles
+)
+
+// Dependency is a directed edge from a plugin to the package it references.
+type Dependency struct {
+	Name       string
+	Kind       DependencyKind
+	Constraint string
+	Optional   bool     // suggest entries, and require-dev entries with DevOptional
+	Via        []string // plugins hidden behind this edge by CollapseChains
+}
+
+type Plugin struct {
+	Name            string
+	FolderName      string // FolderPath with forward slashes, used in labels and IDs
+	FolderPath      string // folder relative to Dir as named on disk
+	Dir             string // plugins directory the folder was found in
+	Version         string
+	Type            string
+	Dependencies    []Dependency
+	IsExternal      bool
+	Metadata        map[string]string
+	Require         map[string]string
+	RequireDev      map[string]string
+	Suggest         map[string]string
+	Conflict        map[string]string // packages (and versions) the plugin cannot be installed with
+	PluginClass     string            // extra.shopware-plugin-class, e.g. Vendor\Plugin\VendorPlugin
+	Deprecated      bool              // marked by a "deprecated" keyword or metadata field
+	Namespaces      []string          // PSR-4 namespace prefixes, e.g. Vendor\Plugin
+	Library         bool              // composer type is not a plugin type, shown with ShowLibraries
+	ShopwareVersion string            // shopware/core constraint, or "unknown"
+	Label           string            // human-friendly name from plugin.xml or extra.label, shown in Graphviz
+	Bundles         []string          // bundle references from extra.shopware-bundles
+	Collapsed       []string          // folder names of the plugins an aggregate node of CollapseVendors stands for
+	Truncated       int               // dependencies a LimitDepth placeholder node stands for
+	Entry           bool              // required by the project composer.json of ProjectScope
+}
+
+// String returns a one-line description for logs and debugging, e.g.
+// "acme/foo (AcmeFoo) deps=3 external=false".
+func (p *Plugin) String() string {
+	return fmt.Sprintf("%s (%s) deps=%d external=%t", p.Name, p.FolderName, len(p.Dependencies), p.IsExternal)
+}
+
+type PluginAnalyzer struct {
+	PluginsDirs            []string
+	VendorLayout           bool              // PluginsDirs are composer vendor/ trees
+	Recursive              bool              // find plugins nested below PluginsDirs
+	ZipFile                string            // scan this zip archive of a plugins directory instead of PluginsDirs
+	PluginTypes            []string          // composer types of plugins; empty means shopware-platform-plugin
+	Locked                 map[string]string // resolved versions from the project composer.lock, by package
+	ShowLibraries          bool              // keep folders of other types as library nodes
+	Plugins                map[string]*Plugin
+	ShowExternalDeps       bool
+	IncludeDev             bool
+	DevOptional            bool // mark require-dev edges as optional
+	ShowSuggest            bool
+	IncludeBundles         bool // draw extra.shopware-bundles references to scanned plugins as bundle edges
+	MergeMutual            bool // draw plugins requiring each other with one red double-headed Graphviz edge
+	ConstrainInternalOnly  bool // external edges don't affect the Graphviz ranking (constraint=false)
+	ShowConflict           bool // draw conflict entries between nodes as red edges
+	HighlightCycles        bool // color plugins on a cycle red in the Mermaid graph
+	MergeEdges             bool
+	ValidateSVG            bool
+	ImageFormat            string // Graphviz output format, one of ImageFormats; empty means svg
+	Engine                 string // Graphviz layout engine, one of LayoutEngines; empty means dot
+	FailFast               bool
+	Title                  string
+	IncludePlatform        bool
+	IgnoredPrefixes        []string // external requirements with these prefixes are dropped, e.g. symfony/
+	ClusterBy              string
+	InternalPrefixes       []string
+	ForcedExternalPrefixes []string              // external even when their folder is scanned, e.g. vendored plugins
+	Outdated               map[string]string     // external package -> latest release, set by CheckUpdates
+	Abandoned              map[string]string     // abandoned packages of the project's composer.lock -> suggested replacement, "" if none
+	Aliases                map[string]string     // alias package name -> canonical name
+	OnlyTypes              []string              // composer types to include as nodes; empty includes all
+	ExcludePatterns        []string              // globs of folder or composer names left out of the scan
+	IgnoreCase             bool                  // match folder names and name globs case-insensitively, as on Windows
+	Workers                int                   // goroutines reading composer.json files; 0 means one per CPU
+	CacheFile              string                // where parsed composer.json files are cached between runs; "" disables it
+	Logger                 *log.Logger           // receives warnings and, with LogLevel Verbose, scan details; nil uses the standard logger
+	IncludePatterns        []string              // globs of folder or composer names to scan; empty includes all
+	TypoDistance           int                   // max edit distance reported as a possible typo; 0 disables
+	Denylist               []string              // package names or glob patterns no plugin may require
+	AllowedExternals       []string              // package names or glob patterns checked by DisallowedExternals
+	Rules                  []DependencyRule      // forbidden edges checked by RuleViolations
+	MetricsIncludeExternal bool                  // count edges to external nodes in Metrics
+	Manifest               *ArchitectureManifest // intended structure checked by VerifyManifest
+	Root                   *RootOverrides        // conflict and replace sections of the project's root composer.json
+	GraphAttrs             []DOTAttribute        // extra graph attributes for Graphviz
+	NodeAttrs              []DOTAttribute        // extra default node attributes for Graphviz
+	EdgeAttrs              []DOTAttribute        // extra default edge attributes for Graphviz
+	Changed                map[string]bool       // composer names highlighted in Graphviz, everything else is dimmed
+	HighlightPath          []string              // composer names of a path drawn in blue in Graphviz, everything else is dimmed
+	ColorByDepth           bool                  // fill Graphviz nodes with a gradient by Depths
+	ColorByConstraint      bool                  // color internal Graphviz edges by how tight their version constraint is
+	LinkTemplate           string                // URL of a Graphviz node, with {folder} and {name} replaced per plugin
+	Theme                  *Theme                // Graphviz colors and node shape; nil uses defaultTheme
+	ExternalDepsCount      map[string]int
+	ScanErrors             []ScanError // plugin folders skipped because composer.json was missing or unreadable
+
+	externalUsers map[string]map[string]bool
+	excluded      map[string]bool     // scanned packages dropped from the graph along with edges to them
+	libraries     map[string]bool     // scanned library folders treated as external packages
+	replacedBy    map[string]string   // packages replaced by an internal plugin -> that plugin
+	providedBy    map[string][]string // virtual packages provided by internal plugins -> those plugins, in scan order
+	duplicates    map[string][]string // composer name -> skipped folders declaring it again
+	cache         *scanCache          // composer.json files parsed by ScanPlugins
+}
+
+func NewPluginAnalyzer(dirs []string, showExternal bool) *PluginAnalyzer {
+	return &PluginAnalyzer{
+		PluginsDirs:       dirs,
+		Plugins:           make(map[string]*Plugin),
+		ShowExternalDeps:  showExternal,
+		DevOptional:       true,
+		HighlightCycles:   true,
+		ExternalDepsCount: make(map[string]int),
+	}
+}
+
+func (pa *PluginAnalyzer) ScanPlugins() error {
+	if pa.ZipFile == "" {
+		for _, dir := range pa.PluginsDirs {
+			if err := validatePluginsDir(dir); err != nil {
+				return err
+			}
+		}
+	}
+	pa.cache = pa.loadScanCache(pa.CacheFile)
+
+	// First pass: collect all internal plugins
+	folders, sources := 0, pa.PluginsDirs
+	if pa.ZipFile != "" {
+		n, err := pa.scanZip(pa.ZipFile)
+		if err != nil {
+			return err
+		}
+		folders, sources = n, []string{pa.ZipFile}
+	} else {
+		for _, dir := range pa.PluginsDirs {
+			n, err := pa.scanDir(dir)
+			if err != nil {
+				return err
+			}
+			folders += n
+		}
+	}
+	if folders == 0 {
+		return noPluginsError(sources)
+	}
+
+	// Second pass: collect dependencies from the requirements parsed in the
+	// first one, now that every internal plugin is known.
+	for _, plugin := range pa.Plugins {
+		for dep, constraint := range plugin.Require {
+			pa.addDependency(plugin, dep, KindRequire, constraint)
+		}
+		if pa.IncludeDev {
+			for dep, constraint := range plugin.RequireDev {
+				pa.addDependency(plugin, dep, KindRequireDev, constraint)
+			}
+		}
+		if pa.ShowSuggest {
+			for dep := range plugin.Suggest {
+				pa.addDependency(plugin, dep, KindSuggest, "")
+			}
+		}
+		if pa.IncludeBundles {
+			pa.addBundleDependencies(plugin)
+		}
+		sortDependencies(plugin)
+	}
+
+	if pa.CacheFile != "" {
+		if err := pa.cache.save(pa.CacheFile); err != nil {
+			pa.warnf("%v", err)
+		}
+	}
+	return nil
+}
+
+// sortDependencies orders a plugin's dependencies by name and kind, since
+// they are collected from composer.json maps in random order.
+func sortDependencies(plugin *Plugin) {
+	sort.SliceStable(plugin.Dependencies, func(i, j int) bool {
+		a, b := plugin.Dependencies[i], plugin.Dependencies[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return kindOrder[a.Kind] < kindOrder[b.Kind]
+	})
+}
+
+// sortedPlugins returns all plugins ordered by folder name, then composer
+// name, so output generated from them is the same on every run.
+func (pa *PluginAnalyzer) sortedPlugins() []*Plugin {
+	plugins := make([]*Plugin, 0, len(pa.Plugins))
+	for _, plugin := range pa.Plugins {
+		plugins = append(plugins, plugin)
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].FolderName != plugins[j].FolderName {
+			return plugins[i].FolderName < plugins[j].FolderName
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// ScanError is a plugin folder ScanPlugins skipped.
+type ScanError struct {
+	Dir    string // plugins directory
+	Folder string
+	Reason string
+}
+
+func (e ScanError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Folder, e.Reason)
+}
+
+// scanFailed records and logs a skipped plugin folder.
+func (pa *PluginAnalyzer) scanFailed(dir, folder, reason string) {
+	scanErr := ScanError{Dir: dir, Folder: folder, Reason: reason}
+	pa.ScanErrors = append(pa.ScanErrors, scanErr)
+	pa.warnf("Skipping %v", scanErr)
+}
+
+// scanDir adds the internal plugins found in one plugins directory and
+// returns the number of plugin folders in it. A composer name already found
+// in an earlier directory keeps its first occurrence.
+func (pa *PluginAnalyzer) scanDir(dir string) (int, error) {
+	folders, err := pa.pluginFolders(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read plugins directory %s: %w", dir, err)
+	}
+
+	paths := make([]string, len(folders))
+	for i, folder := range folders {
+		paths[i] = filepath.Join(dir, folder, "composer.json")
+	}
+	// Reading is done concurrently; the results are applied in folder order
+	// so warnings and the choice between duplicates stay deterministic.
+	results := pa.readComposers(paths)
+	if err := pa.addPlugins(os.DirFS(dir), dir, folders, paths, results); err != nil {
+		return 0, err
+	}
+	return len(folders), nil
+}
+
+// addPlugins adds the plugins read from the composer.json files at paths,
+// one per folder of dir. Sidecar files such as plugin.xml are read from
+// fsys, which holds the folders.
+func (pa *PluginAnalyzer) addPlugins(fsys fs.FS, dir string, folders, paths []string, results []composerResult) error {
+	for i, folder := range folders {
+		composerPath, res := paths[i], results[i]
+		folderName := normalizeFolderName(folder)
+		if res.missing {
+			pa.scanFailed(dir, folder, "no composer.json found")
+			continue
+		}
+
+		entry := res.entry
+		if !res.cached {
+			if res.err != nil {
+				action, verb := "reading", "read"
+				if res.parseErr {
+					action, verb = "parsing", "parse"
+				}
+				if pa.FailFast {
+					return fmt.Errorf("failed to %s %s: %w", verb, composerPath, res.err)
+				}
+				pa.scanFailed(dir, folder, fmt.Sprintf("error %s composer.json: %v", action, res.err))
+				continue
+			}
+			entry = pa.cache.put(composerPath, entry.Composer, entry.Encoding)
+		}
+		if entry.Encoding != "" {
+			pa.warnf("%s is encoded as %s, converted to UTF-8", composerPath, entry.Encoding)
+		}
+
+		composer := entry.Composer
+		composer.Name = normalizePackageName(composer.Name)
+		if composer.Name == "" {
+			composer.Name = unnamedPackageName(folderName)
+			pa.warnf("%s has no name, using %s", composerPath, composer.Name)
+		}
+
+		if pa.isForcedExternal(composer.Name) {
+			continue
+		}
+		if pa.VendorLayout && !isShopwarePluginType(composer.Type) {
+			// Libraries installed next to the plugins stay external.
+			continue
+		}
+		if !pa.typeIncluded(composer.Type) || !pa.nameIncluded(folderName, composer.Name) {
+			pa.exclude(composer.Name)
+			continue
+		}
+		library := false
+		switch {
+		case pa.VendorLayout:
+		case composer.Type == "":
+			pa.warnf("%s has no composer type, treating it as a plugin", composerPath)
+		case !pa.isPluginType(composer.Type):
+			if !pa.ShowLibraries {
+				// Requirements on it become external dependencies.
+				if pa.libraries == nil {
+					pa.libraries = make(map[string]bool)
+				}
+				pa.libraries[composer.Name] = true
+				continue
+			}
+			library = true
+		}
+
+		if existing, ok := pa.Plugins[composer.Name]; ok {
+			pa.warnf("duplicate plugin name %s in %s, keeping the one in %s",
+				composer.Name, filepath.Join(dir, folder), filepath.Join(existing.Dir, existing.FolderPath))
+			if pa.duplicates == nil {
+				pa.duplicates = make(map[string][]string)
+			}
+			pa.duplicates[composer.Name] = append(pa.duplicates[composer.Name], filepath.Join(dir, folder))
+			continue
+		}
+
+		metadata, err := loadPluginMetadata(fsys, filepath.ToSlash(folder))
+		if err != nil {
+			pa.warnf("Ignoring metadata of %s: %v", folder, err)
+		}
+		label, err := loadPluginLabel(fsys, filepath.ToSlash(folder))
+		if err != nil {
+			pa.warnf("Ignoring label of %s: %v", folder, err)
+		}
+		if label == "" {
+			label = composerLabel(composer.Extra.Label)
+		}
+
+		require := normalizeRequirements(composer.Require)
+		pa.Plugins[composer.Name] = &Plugin{
+			Name:            composer.Name,
+			FolderName:      folderName,
+			FolderPath:      folder,
+			Dir:             dir,
+			Version:         composer.Version,
+			Type:            composer.Type,
+			IsExternal:      false,
+			Metadata:        metadata,
+			PluginClass:     composer.Extra.ShopwarePluginClass,
+			Deprecated:      isDeprecated(composer.Keywords, metadata),
+			Namespaces:      psr4Namespaces(composer.Autoload),
+			Library:         library,
+			Require:         require,
+			RequireDev:      normalizeRequirements(composer.RequireDev),
+			Suggest:         normalizeRequirements(composer.Suggest),
+			Conflict:        normalizeRequirements(composer.Conflict),
+			ShopwareVersion: shopwareVersion(require),
+			Label:           label,
+			Bundles:         shopwareBundles(composer.Extra.ShopwareBundles),
+		}
+		for replaced := range normalizeRequirements(composer.Replace) {
+			if pa.replacedBy == nil {
+				pa.replacedBy = make(map[string]string)
+			}
+			if _, ok := pa.replacedBy[replaced]; !ok {
+				pa.replacedBy[replaced] = composer.Name
+			}
+		}
+		for provided := range normalizeRequirements(composer.Provide) {
+			if pa.providedBy == nil {
+				pa.providedBy = make(map[string][]string)
+			}
+			pa.providedBy[provided] = append(pa.providedBy[provided], composer.Name)
+		}
+	}
+	return nil
+}
+
+// typeIncluded reports whether a plugin with the given composer type passes
+// the OnlyTypes filter.
+func (pa *PluginAnalyzer) typeIncluded(composerType string) bool {
+	if len(pa.OnlyTypes) == 0 {
+		return true
+	}
+	for _, t := range pa.OnlyTypes {
+		if t == composerType {
+			return true
+		}
+	}
+	return false
+}
+
+// externalVersion returns the version shown on an external node: the
+// locked version as "@1.2.3", or else the constraints the plugins require
+// it with. It returns "" if neither is known.
+func (pa *PluginAnalyzer) externalVersion(name string) string {
+	if version, ok := pa.Locked[name]; ok {
+		return "@" + version
+	}
+	seen := make(map[string]bool)
+	var constraints []string
+	for _, plugin := range pa.Plugins {
+		for _, dep := range plugin.Dependencies {
+			if dep.Name == name && dep.Constraint != "" && !seen[dep.Constraint] {
+				seen[dep.Constraint] = true
+				constraints = append(constraints, dep.Constraint)
+			}
+		}
+	}
+	sort.Strings(constraints)
+	return strings.Join(constraints, ", ")
+}
+
+// DefaultPluginType is the composer type of plugins when PluginTypes is
+// empty.
+const DefaultPluginType = "shopware-platform-plugin"
+
+// isPluginType reports whether a composer type marks a plugin rather than
+// a library.
+func (pa *PluginAnalyzer) isPluginType(composerType string) bool {
+	if len(pa.PluginTypes) == 0 {
+		return composerType == DefaultPluginType
+	}
+	for _, t := range pa.PluginTypes {
+		if t == composerType {
+			return true
+		}
+	}
+	return false
+}
+
+// nameIncluded reports whether a plugin passes the ExcludePatterns and
+// IncludePatterns filters, matching its folder or composer name. Exclusion
+// wins over inclusion.
+func (pa *PluginAnalyzer) nameIncluded(folder, name string) bool {
+	excludes, includes := pa.ExcludePatterns, pa.IncludePatterns
+	if pa.IgnoreCase {
+		excludes, includes = lowerAll(excludes), lowerAll(includes)
+		folder, name = strings.ToLower(folder), strings.ToLower(name)
+	}
+	if matchesAny(excludes, folder, name) {
+		return false
+	}
+	return len(includes) == 0 || matchesAny(includes, folder, name)
+}
+
+// lowerAll returns the strings lowercased.
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
+
+// matchesAny reports whether one of the filepath.Match patterns matches one
+// of the names. Malformed patterns match nothing.
+func matchesAny(patterns []string, names ...string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exclude drops a scanned package from the graph. Requirements on it are
+// ignored rather than turned into external dependencies.
+func (pa *PluginAnalyzer) exclude(name string) {
+	if pa.excluded == nil {
+		pa.excluded = make(map[string]bool)
+	}
+	pa.excluded[name] = true
+}
+
+// addDependency records an edge from plugin to dep, creating an external node
+// and counting the usage when dep is not one of the scanned plugins.
+func (pa *PluginAnalyzer) addDependency(plugin *Plugin, dep string, kind DependencyKind, constraint string) {
+	if !pa.isTrackedPackage(dep) {
+		return
+	}
+	dep = pa.canonicalName(dep)
+	if dep == plugin.Name {
+		pa.warnf("self-dependency: %s lists its own package %s in %s, ignored", plugin.FolderName, dep, kind)
+		return
+	}
+	if pa.excluded[dep] || hasDependency(plugin, dep, kind) {
+		return
+	}
+	if existing, isInternal := pa.Plugins[dep]; pa.replacedByRoot(dep) && (!isInternal || existing.IsExternal) {
+		// Provided by the project root, nothing gets installed for it.
+		return
+	}
+
+	optional := kind == KindSuggest || (kind == KindRequireDev && pa.DevOptional)
+	edge := Dependency{Name: dep, Kind: kind, Constraint: constraint, Optional: optional}
+	if existing, isInternal := pa.Plugins[dep]; isInternal && !existing.IsExternal {
+		plugin.Dependencies = append(plugin.Dependencies, edge)
+		pa.debugf("Edge %s -> %s (%s)", plugin.Name, dep, kind)
+		return
+	}
+
+	if pa.ShowExternalDeps {
+		plugin.Dependencies = append(plugin.Dependencies, edge)
+		pa.debugf("Edge %s -> %s (%s, external)", plugin.Name, dep, kind)
+		// Create external plugin node if it doesn't exist
+		if _, exists := pa.Plugins[dep]; !exists {
+			pa.Plugins[dep] = &Plugin{
+				Name:       dep,
+				FolderName: dep,
+				IsExternal: true,
+			}
+		}
+	}
+	if kind != KindSuggest {
+		pa.countExternalUse(dep, plugin)
+	}
+}
+
+func (pa *PluginAnalyzer) GenerateMermaid() string {
+	var sb strings.Builder
+	if pa.Title != "" {
+		sb.WriteString(fmt.Sprintf("---\ntitle: %q\n---\n", pa.titleText()))
+	}
+	sb.WriteString("graph TD\n")
+
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+
+		for _, edge := range pa.renderedEdges(plugin) {
+			depPlugin := pa.Plugins[edge.Target]
+			if depPlugin.IsExternal && !pa.ShowExternalDeps {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("    \"%s\" %s \"%s\"\n", plugin.FolderName, edge.mermaidArrow(), depPlugin.FolderName))
+		}
+	}
+
+	if pa.ShowConflict {
+		for _, edge := range pa.conflictEdges() {
+			sb.WriteString(fmt.Sprintf("    \"%s\" -.-x|conflict| \"%s\"\n", pa.Plugins[edge.From].FolderName, pa.Plugins[edge.To].FolderName))
+		}
+	}
+
+	if pa.HighlightCycles {
+		if names := pa.cycleNodes(); len(names) > 0 {
+			sb.WriteString("    classDef cycle fill:#ffcccc,stroke:#cc0000,color:#cc0000\n")
+			for _, name := range names {
+				sb.WriteString(fmt.Sprintf("    class \"%s\" cycle;\n", pa.Plugins[name].FolderName))
+			}
+		}
+	}
+
+	var entries []string
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.Entry {
+			entries = append(entries, plugin.FolderName)
+		}
+	}
+	if len(entries) > 0 {
+		sb.WriteString("    classDef entry stroke-width:3px\n")
+		for _, folder := range entries {
+			sb.WriteString(fmt.Sprintf("    class \"%s\" entry;\n", folder))
+		}
+	}
+
+	// Known internal packages that weren't scanned are drawn as in
+	// GenerateDOT, with or without ShowExternalDeps.
+	if missing := pa.MissingInternalDependencies(); len(missing) > 0 {
+		if !pa.ShowExternalDeps {
+			for _, m := range missing {
+				for _, folder := range m.RequiredBy {
+					sb.WriteString(fmt.Sprintf("    \"%s\" -.-> \"%s\"\n", folder, m.Name))
+				}
+			}
+		}
+		sb.WriteString("    classDef unscanned stroke:#cc0000,stroke-dasharray:5 5,color:#cc0000\n")
+		for _, m := range missing {
+			sb.WriteString(fmt.Sprintf("    class \"%s\" unscanned;\n", m.Name))
+		}
+	}
+
+	return sb.String()
+}
+
+// GenerateDOT writes the graph in Graphviz DOT format to w. Node and edge
+// statements are written as they are produced, so memory use doesn't grow
+// with the size of the output.
+func (pa *PluginAnalyzer) GenerateDOT(w io.Writer) error {
+	theme := pa.theme()
+	dotContent := bufio.NewWriter(w)
+	dotContent.WriteString("digraph PluginDependencies {\n")
+	dotContent.WriteString("    rankdir=TB;\n")
+	fmt.Fprintf(dotContent, "    node [shape=%s, style=rounded];\n", theme.NodeShape)
+	fmt.Fprintf(dotContent, "    edge [color=\"%s\"];\n", theme.EdgeColor)
+	if pa.Title != "" {
+		fmt.Fprintf(dotContent, "    label=\"%s\";\n    labelloc=t;\n    fontsize=20;\n", escapeDOT(pa.titleText()))
+	}
+	// User attributes come last so they override the defaults above.
+	for _, attr := range pa.GraphAttrs {
+		fmt.Fprintf(dotContent, "    %s=\"%s\";\n", attr.Key, attr.Value)
+	}
+	if len(pa.NodeAttrs) > 0 {
+		fmt.Fprintf(dotContent, "    node [%s];\n", joinDOTAttributes(pa.NodeAttrs))
+	}
+	if len(pa.EdgeAttrs) > 0 {
+		fmt.Fprintf(dotContent, "    edge [%s];\n", joinDOTAttributes(pa.EdgeAttrs))
+	}
+
+	// Add nodes
+	var nodes []*Plugin
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		nodes = append(nodes, plugin)
+	}
+	var depths map[string]int
+	maxDepth := 0
+	if pa.ColorByDepth {
+		depths = pa.Depths()
+		for _, d := range depths {
+			if d > maxDepth {
+				maxDepth = d
+			}
+		}
+	}
+	var devOnly map[string]bool
+	if theme.DevFill != "" {
+		devOnly = pa.devOnlyNodes()
+	}
+	pa.writeDOTNodes(dotContent, nodes, func(plugin *Plugin) string {
+		if pa.unscannedInternal(plugin) {
+			return missingDOTNode(plugin.Name)
+		}
+		if plugin.Truncated > 0 {
+			return truncatedDOTNode(plugin)
+		}
+		style := "rounded,filled"
+		fillColor := theme.InternalFill
+		label := escapeDOT(plugin.displayName())
+		if len(plugin.Collapsed) > 0 {
+			label += "\\n" + collapsedLabel(plugin)
+			if plugin.IsExternal {
+				fillColor = theme.ExternalFill
+			}
+		} else if plugin.IsExternal {
+			fillColor = theme.ExternalFill
+			if users := pa.ExternalDepsCount[plugin.Name]; users > 0 {
+				label += " (" + usersLabel(users) + ")"
+			}
+			if version := pa.externalVersion(plugin.Name); pa.Locked != nil && version != "" {
+				label += "\\n" + version
+			}
+		} else {
+			label += "\\n" + VersionLabel(plugin) + "\\nShopware " + escapeDOT(plugin.ShopwareVersion)
+			if pa.ColorByDepth {
+				fillColor = depthColor(depths[plugin.Name], maxDepth)
+			}
+		}
+		if devOnly[plugin.Name] {
+			fillColor = theme.DevFill
+		}
+		if plugin.Library {
+			fillColor = "#dde8f8" // Light blue for libraries
+			label += "\\n(library)"
+		}
+		if latest, ok := pa.Outdated[plugin.Name]; ok {
+			label += fmt.Sprintf("\\noutdated (latest %s)", latest)
+		}
+		if plugin.Deprecated {
+			fillColor = "#fff3c4"
+			style += ",dashed"
+			label += "\\n(deprecated)"
+		}
+		extra := ""
+		if _, ok := pa.Abandoned[plugin.Name]; ok && plugin.IsExternal {
+			fillColor = "#ffd8a8"
+			extra = ", color=\"#cc6600\", penwidth=2"
+			label += "\\n(abandoned)"
+		}
+		if len(pa.Changed) > 0 {
+			if pa.Changed[plugin.Name] {
+				fillColor = "#ffd27f"
+				extra = ", color=\"#e67e00\", penwidth=2"
+			} else {
+				extra = ", color=\"#cccccc\", fontcolor=\"#999999\""
+			}
+		}
+		if len(pa.HighlightPath) > 0 {
+			if pa.onHighlightedPath(plugin.Name) {
+				extra = ", color=\"#1a5fb4\", fontcolor=\"#1a5fb4\", penwidth=3"
+			} else {
+				extra = ", color=\"#cccccc\", fontcolor=\"#999999\""
+			}
+		}
+		if plugin.Entry {
+			extra += ", peripheries=2"
+		}
+		if pa.LinkTemplate != "" {
+			extra += fmt.Sprintf(", URL=\"%s\", target=\"_blank\"", escapeDOT(pa.nodeURL(plugin)))
+		}
+
+		return fmt.Sprintf("    \"%s\" [label=\"%s\", fillcolor=\"%s\", style=\"%s\", class=\"%s\"%s];\n",
+			plugin.Name, label, fillColor, style, pa.nodeClass(plugin), extra)
+	})
+
+	// Add edges
+	var mutual map[string]map[string]bool
+	if pa.MergeMutual {
+		mutual = pa.mutualPairs()
+	}
+	merged := make(map[string]bool)
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+
+		for _, edge := range pa.renderedEdges(plugin) {
+			depPlugin := pa.Plugins[edge.Target]
+			if depPlugin.IsExternal && !pa.ShowExternalDeps {
+				continue
+			}
+			if mutual[plugin.Name][edge.Target] {
+				// One double-headed edge stands for both directions.
+				pair := pairKey(plugin.Name, edge.Target)
+				if !merged[pair] {
+					merged[pair] = true
+					fmt.Fprintf(dotContent, "    \"%s\" -> \"%s\" [dir=both, color=\"#cc0000\", penwidth=2];\n", plugin.Name, edge.Target)
+				}
+				continue
+			}
+			fmt.Fprintf(dotContent, "    \"%s\" -> \"%s\"%s;\n", plugin.Name, edge.Target, edge.dotAttributes(theme))
+		}
+	}
+
+	if pa.ShowConflict {
+		for _, edge := range pa.conflictEdges() {
+			fmt.Fprintf(dotContent, "    \"%s\" -> \"%s\" [color=\"#cc0000\", style=dashed, arrowhead=tee, label=\"conflict %s\", fontcolor=\"#cc0000\", fontsize=10];\n",
+				edge.From, edge.To, escapeDOT(edge.Constraint))
+		}
+	}
+
+	// With -show-external the missing plugins are already external nodes
+	// above; without it they would vanish along with their edges.
+	if !pa.ShowExternalDeps {
+		for _, missing := range pa.MissingInternalDependencies() {
+			dotContent.WriteString(missingDOTNode(missing.Name))
+			for _, folder := range missing.RequiredBy {
+				fmt.Fprintf(dotContent, "    \"%s\" -> \"%s\" [color=\"#cc0000\", style=dashed];\n", pa.PluginByFolder(folder).Name, missing.Name)
+			}
+		}
+	}
+
+	dotContent.WriteString("}\n")
+
+	// bufio.Writer keeps the first write error and returns it here.
+	return dotContent.Flush()
+}
+
+// missingDOTNode returns the node statement for a required internal
+// package that no scanned folder provides: red, dashed and labeled as not
+// scanned.
+func missingDOTNode(name string) string {
+	return fmt.Sprintf("    \"%s\" [label=\"%s\\n(internal, not scanned)\", color=\"#cc0000\", fontcolor=\"#cc0000\", style=\"rounded,dashed\"];\n", name, name)
+}
+
+// truncatedDOTNode renders a LimitDepth placeholder as a small dashed node.
+func truncatedDOTNode(plugin *Plugin) string {
+	return fmt.Sprintf("    \"%s\" [label=\"...\\n%d hidden\", color=\"#888888\", fontcolor=\"#888888\", style=\"rounded,dashed\"];\n", plugin.Name, plugin.Truncated)
+}
+
+func (pa *PluginAnalyzer) GenerateGraphviz(outputPath string) error {
+	format := pa.ImageFormat
+	if format == "" {
+		format = "svg"
+	}
+	if !ImageFormats[format] {
+		return fmt.Errorf("unsupported image format %q (expected svg, png or pdf)", format)
+	}
+
+	dot := pa.streamDOT()
+	defer dot.Close()
+	if err := pa.runLayout(dot, nil, "-T"+format, "-o", outputPath); err != nil {
+		return err
+	}
+
+	return verifyGraphvizOutput(outputPath, pa.ValidateSVG && format == "svg")
+}
+
+// graphvizCommand creates the command running a Graphviz layout engine.
+// It is a variable so the engine can be replaced, e.g. by a fake in tests.
+var graphvizCommand = exec.Command
+
+// streamDOT returns a reader of the DOT content, generated while it is read.
+// Streaming it to the engine's stdin leaves no temporary file behind if the
+// run is interrupted. Closing the reader unblocks GenerateDOT if the engine
+// quits early.
+func (pa *PluginAnalyzer) streamDOT() io.ReadCloser {
+	dot, w := io.Pipe()
+	go func() {
+		w.CloseWithError(pa.GenerateDOT(w))
+	}()
+	return dot
+}
+
+// runLayout runs the configured layout engine with args on the DOT content
+// read from dot, writing what it prints to stdout, if not nil.
+func (pa *PluginAnalyzer) runLayout(dot io.Reader, stdout io.Writer, args ...string) error {
+	engine := pa.layoutEngine()
+	if !LayoutEngines[engine] {
+		return fmt.Errorf("unknown layout engine %q", engine)
+	}
+	cmd := graphvizCommand(engine, args...)
+	cmd.Stdin = dot
+	cmd.Stdout = stdout
+	return runGraphviz(cmd)
+}
+
+// RenderSVG lays out the graph as SVG and returns it. The engine writes the
+// SVG to its stdout, so no file is created.
+func (pa *PluginAnalyzer) RenderSVG() ([]byte, error) {
+	dot := pa.streamDOT()
+	defer dot.Close()
+	var svg bytes.Buffer
+	if err := pa.runLayout(dot, &svg, "-Tsvg"); err != nil {
+		return nil, err
+	}
+	if svg.Len() == 0 {
+		return nil, errors.New("dot reported success but produced no SVG")
+	}
+	if pa.ValidateSVG {
+		if err := validateSVG(bytes.NewReader(svg.Bytes()), "SVG output"); err != nil {
+			return nil, err
+		}
+	}
+	return svg.Bytes(), nil
+}
+
+// VersionLabel returns the version of a plugin as shown in labels and the
+// summary, e.g. "v2.3.1", or "(no version)" if composer.json has none.
+func VersionLabel(plugin *Plugin) string {
+	if plugin.Version == "" {
+		return "(no version)"
+	}
+	return "v" + strings.TrimPrefix(plugin.Version, "v")
+}
+
+// ImageFormats are the Graphviz output formats GenerateGraphviz supports.
+var ImageFormats = map[string]bool{"svg": true, "png": true, "pdf": true}
+
+// titleText returns the configured title followed by the generation date.
+func (pa *PluginAnalyzer) titleText() string {
+	return fmt.Sprintf("%s (generated %s)", pa.Title, time.Now().Format("2006-01-02"))
+}
+
+// escapeDOT escapes a string for use inside a double-quoted DOT ID.
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+// nodeURL returns LinkTemplate with {folder} replaced by the plugin's
+// folder name, escaped for a URL path, and {name} by its composer name.
+func (pa *PluginAnalyzer) nodeURL(plugin *Plugin) string {
+	return strings.NewReplacer("{folder}", url.PathEscape(plugin.FolderName), "{name}", plugin.Name).Replace(pa.LinkTemplate)
+}
+
+// verifyGraphvizOutput checks that dot actually produced a non-empty file,
+// since some Graphviz builds exit successfully without writing anything.
+// When checkSVG is set, the file must also be well-formed XML with an <svg>
+// root element.
+func verifyGraphvizOutput(outputPath string, checkSVG bool) error {
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return fmt.Errorf("dot reported success but produced no output file %s: %w", outputPath, err)
+	}
+	if info.Size() == 0 {
+		return fmt.Errorf("dot reported success but produced an empty file %s", outputPath)
+	}
+	if !checkSVG {
+		return nil
+	}
+
+	f, err := os.Open(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s for validation: %w", outputPath, err)
+	}
+	defer f.Close()
+	return validateSVG(f, "output file "+outputPath)
+}
+
+// validateSVG checks that r holds well-formed XML with an <svg> root; name
+// describes r in errors.
+func validateSVG(r io.Reader, name string) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("%s is not valid SVG: %w", name, err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "svg" {
+				return fmt.Errorf("%s is not valid SVG: root element is <%s>", name, start.Name.Local)
+			}
+			break
+		}
+	}
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s is not valid SVG: %w", name, err)
+		}
+	}
+}
+
+// LayoutEngines are the Graphviz layout programs -engine accepts.
+var LayoutEngines = map[string]bool{"dot": true, "neato": true, "fdp": true, "sfdp": true, "circo": true}
+
+// CheckGraphvizInstalled reports whether the given Graphviz layout engine
+// is on the PATH.
+func CheckGraphvizInstalled(engine string) bool {
+	_, err := exec.LookPath(engine)
+	return err == nil
+}
+
+// layoutEngine returns the configured Graphviz engine, dot by default.
+func (pa *PluginAnalyzer) layoutEngine() string {
+	if pa.Engine == "" {
+		return "dot"
+	}
+	return pa.Engine
+}
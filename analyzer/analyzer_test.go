@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writePlugins creates a plugins directory with one folder per entry of
+// composers, holding the given composer.json content, and returns its path.
+func writePlugins(t *testing.T, composers map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for folder, composer := range composers {
+		path := filepath.Join(dir, folder, "composer.json")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(composer), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// newTestAnalyzer returns an analyzer for dir whose diagnostics go to the
+// returned buffer.
+func newTestAnalyzer(dir string) (*PluginAnalyzer, *bytes.Buffer) {
+	var logs bytes.Buffer
+	pa := NewPluginAnalyzer([]string{dir}, false)
+	pa.Logger = log.New(&logs, "", 0)
+	return pa, &logs
+}
+
+// scanFixture scans a plugins directory created by writePlugins from
+// composers, after applying the given options to the analyzer, and returns
+// the analyzer and its diagnostics.
+func scanFixture(t *testing.T, composers map[string]string, options ...func(*PluginAnalyzer)) (*PluginAnalyzer, *bytes.Buffer) {
+	t.Helper()
+	pa, logs := newTestAnalyzer(writePlugins(t, composers))
+	for _, option := range options {
+		option(pa)
+	}
+	if err := pa.ScanPlugins(); err != nil {
+		t.Fatalf("ScanPlugins: %v", err)
+	}
+	return pa, logs
+}
+
+// fakeGraphviz replaces graphvizCommand for the test with this test binary
+// running TestFakeGraphvizProcess, which echoes its arguments and the DOT it
+// reads in an SVG on stdout.
+func fakeGraphviz(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() { graphvizCommand = exec.Command })
+	graphvizCommand = func(name string, args ...string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestFakeGraphvizProcess", "--", name}, args...)...)
+		cmd.Env = append(os.Environ(), "FAKE_GRAPHVIZ=1")
+		return cmd
+	}
+}
+
+func TestFakeGraphvizProcess(t *testing.T) {
+	if os.Getenv("FAKE_GRAPHVIZ") != "1" {
+		t.Skip("only run as the fake Graphviz engine")
+	}
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	dot, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		os.Exit(1)
+	}
+	fmt.Printf("<svg><desc>%s</desc><text>%s</text></svg>\n", strings.Join(args[1:], " "), html.EscapeString(string(dot)))
+	os.Exit(0)
+}
+
+func TestRenderSVGStreamsThroughEngine(t *testing.T) {
+	fakeGraphviz(t)
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b"}`,
+	})
+	pa.ValidateSVG = true
+	svg, err := pa.RenderSVG()
+	if err != nil {
+		t.Fatalf("RenderSVG: %v", err)
+	}
+	if !strings.HasPrefix(string(svg), "<svg><desc>dot -Tsvg</desc>") {
+		t.Errorf("engine not run as `dot -Tsvg` writing to stdout, got:\n%s", svg)
+	}
+	if !strings.Contains(string(svg), "digraph") {
+		t.Errorf("SVG doesn't hold the DOT sent to the engine:\n%s", svg)
+	}
+	if entries, _ := os.ReadDir(tmp); len(entries) > 0 {
+		t.Errorf("RenderSVG left %d temporary files", len(entries))
+	}
+}
+
+// showExternal is a scanFixture option setting ShowExternalDeps.
+func showExternal(pa *PluginAnalyzer) {
+	pa.ShowExternalDeps = true
+}
+
+func TestMermaidCycleClasses(t *testing.T) {
+	cyclic, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b", "require": {"v/a": "*"}}`,
+		"C": `{"name": "v/c", "require": {"v/a": "*"}}`,
+	})
+	mermaid := cyclic.GenerateMermaid()
+	for _, want := range []string{"classDef cycle ", `class "A" cycle;`, `class "B" cycle;`} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("cyclic graph lacks %s:\n%s", want, mermaid)
+		}
+	}
+	if strings.Contains(mermaid, `class "C" cycle;`) {
+		t.Errorf("C is not on the cycle but classed as such:\n%s", mermaid)
+	}
+
+	cyclic.HighlightCycles = false
+	if mermaid := cyclic.GenerateMermaid(); strings.Contains(mermaid, " cycle") {
+		t.Errorf("cycle classes without HighlightCycles:\n%s", mermaid)
+	}
+
+	acyclic, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b"}`,
+	})
+	if mermaid := acyclic.GenerateMermaid(); strings.Contains(mermaid, " cycle") {
+		t.Errorf("acyclic graph has cycle classes:\n%s", mermaid)
+	}
+}
+
+func TestMixedCaseEdgeResolution(t *testing.T) {
+	for _, ignoreCase := range []bool{false, true} {
+		pa, _ := scanFixture(t, map[string]string{
+			"PluginA": `{"name": "Vendor/Plugin-A"}`,
+			"PluginB": `{"name": "vendor/plugin-b", "require": {"vendor/plugin-a": "*"}}`,
+			"PluginC": `{"name": "VENDOR/PLUGIN-C", "require": {"Vendor/PLUGIN-a": "*", "Vendor/Plugin-B": "*"}}`,
+		}, showExternal, func(pa *PluginAnalyzer) {
+			pa.IgnoreCase = ignoreCase
+		})
+
+		for name, plugin := range pa.Plugins {
+			if plugin.IsExternal {
+				t.Errorf("IgnoreCase=%v: %s resolved to an external node", ignoreCase, name)
+			}
+		}
+		var got []string
+		for _, dep := range pa.Plugins["vendor/plugin-c"].Dependencies {
+			got = append(got, dep.Name)
+		}
+		if want := []string{"vendor/plugin-a", "vendor/plugin-b"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("IgnoreCase=%v: dependencies of PluginC = %q, want %q", ignoreCase, got, want)
+		}
+		if folder := pa.Plugins["vendor/plugin-a"].FolderName; folder != "PluginA" {
+			t.Errorf("IgnoreCase=%v: FolderName = %q, want the original PluginA", ignoreCase, folder)
+		}
+	}
+}
+
+func TestIncludeExcludePatterns(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"Core":     `{"name": "vendor/core"}`,
+		"Shop":     `{"name": "vendor/shop", "require": {"vendor/core": "*", "vendor/testkit": "*", "vendor/legacy": "*"}}`,
+		"TestKit":  `{"name": "vendor/testkit"}`,
+		"OldStuff": `{"name": "vendor/legacy"}`,
+		"Other":    `{"name": "acme/other", "require": {"vendor/testkit": "*"}}`,
+		"Misc":     `{"name": "acme/misc"}`,
+	}, showExternal, func(pa *PluginAnalyzer) {
+		// Folder and composer name globs on both sides; TestKit and
+		// OldStuff match an include as well, but exclusion wins.
+		pa.ExcludePatterns = []string{"Test*", "vendor/leg*"}
+		pa.IncludePatterns = []string{"vendor/*", "Oth*"}
+	})
+
+	if got, want := pa.InternalPluginNames(), []string{"acme/other", "vendor/core", "vendor/shop"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("scanned plugins = %q, want %q", got, want)
+	}
+	for _, name := range []string{"vendor/testkit", "vendor/legacy", "acme/misc"} {
+		if _, ok := pa.Plugins[name]; ok {
+			t.Errorf("filtered %s is still in the graph", name)
+		}
+	}
+	var deps []string
+	for _, dep := range pa.Plugins["vendor/shop"].Dependencies {
+		deps = append(deps, dep.Name)
+	}
+	if want := []string{"vendor/core"}; !reflect.DeepEqual(deps, want) {
+		t.Errorf("dependencies of Shop = %q, want %q", deps, want)
+	}
+	if deps := pa.Plugins["acme/other"].Dependencies; len(deps) != 0 {
+		t.Errorf("Other keeps its edge to the excluded TestKit: %v", deps)
+	}
+
+	var dot strings.Builder
+	if err := pa.GenerateDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	data, err := pa.GenerateJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for format, output := range map[string]string{"mermaid": pa.GenerateMermaid(), "dot": dot.String(), "json": string(data)} {
+		for _, filtered := range []string{"TestKit", "testkit", "OldStuff", "legacy", "Misc", "misc"} {
+			if strings.Contains(output, filtered) {
+				t.Errorf("%s output mentions the filtered %s:\n%s", format, filtered, output)
+			}
+		}
+	}
+}
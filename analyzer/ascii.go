@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// asciiLaneGap is the horizontal distance between two edge lanes.
+const asciiLaneGap = 3
+
+// asciiEdge is an edge between two rows of the ASCII diagram.
+type asciiEdge struct {
+	from, to int // node rows
+	weak     bool
+	lane     int
+}
+
+// asciiOrder returns the visible nodes with dependents before their
+// dependencies where the graph allows it, so most edges point downwards.
+func (pa *PluginAnalyzer) asciiOrder() []*Plugin {
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return pa.Plugins[names[i]].FolderName < pa.Plugins[names[j]].FolderName
+	})
+
+	visited := make(map[string]bool)
+	var postOrder []*Plugin
+	var visit func(name string)
+	visit = func(name string) {
+		visited[name] = true
+		for _, dep := range pa.Plugins[name].Dependencies {
+			if !visited[dep.Name] {
+				visit(dep.Name)
+			}
+		}
+		postOrder = append(postOrder, pa.Plugins[name])
+	}
+	for _, name := range names {
+		if !visited[name] {
+			visit(name)
+		}
+	}
+
+	for i, j := 0, len(postOrder)-1; i < j; i, j = i+1, j-1 {
+		postOrder[i], postOrder[j] = postOrder[j], postOrder[i]
+	}
+	return postOrder
+}
+
+// GenerateASCII renders the graph as text. Graphs with at most maxNodes
+// nodes are drawn as a column of boxes with the edges routed in lanes to the
+// right of it; an arrow "<" marks the dependency end, and dotted lanes are
+// require-dev or suggest edges. Larger graphs fall back to a sorted edge
+// list, which stays readable at any size.
+func (pa *PluginAnalyzer) GenerateASCII(maxNodes int) string {
+	nodes := pa.asciiOrder()
+	if len(nodes) > maxNodes {
+		return pa.asciiEdgeList(nodes)
+	}
+
+	row := make(map[string]int)
+	width := 0
+	for i, plugin := range nodes {
+		row[plugin.Name] = i
+		if len(plugin.FolderName) > width {
+			width = len(plugin.FolderName)
+		}
+	}
+	boxWidth := width + 4
+
+	var edges []*asciiEdge
+	for i, plugin := range nodes {
+		for _, edge := range pa.renderedEdges(plugin) {
+			j, ok := row[edge.Target]
+			if !ok || j == i {
+				continue
+			}
+			edges = append(edges, &asciiEdge{from: i, to: j, weak: edge.Kinds[0] != KindRequire})
+		}
+	}
+
+	// Each node occupies three lines; edges leave from the bottom border of
+	// the source box and arrive at the top border of the target box.
+	span := func(e *asciiEdge) (int, int) {
+		a, b := 3*e.from+2, 3*e.to
+		if a > b {
+			a, b = b, a
+		}
+		return a, b
+	}
+	sort.SliceStable(edges, func(i, j int) bool {
+		ai, bi := span(edges[i])
+		aj, bj := span(edges[j])
+		return bi-ai < bj-aj
+	})
+	var lanes [][]*asciiEdge
+	for _, e := range edges {
+		a, b := span(e)
+		e.lane = len(lanes)
+		for k, lane := range lanes {
+			free := true
+			for _, other := range lane {
+				oa, ob := span(other)
+				if a <= ob && oa <= b {
+					free = false
+					break
+				}
+			}
+			if free {
+				e.lane = k
+				break
+			}
+		}
+		if e.lane == len(lanes) {
+			lanes = append(lanes, nil)
+		}
+		lanes[e.lane] = append(lanes[e.lane], e)
+	}
+
+	grid := make([][]byte, 3*len(nodes))
+	for i := range grid {
+		grid[i] = []byte(strings.Repeat(" ", boxWidth+1+asciiLaneGap*len(lanes)))
+	}
+	for i, plugin := range nodes {
+		border := "+" + strings.Repeat("-", boxWidth-2) + "+"
+		copy(grid[3*i], border)
+		copy(grid[3*i+1], fmt.Sprintf("| %-*s |", width, plugin.FolderName))
+		copy(grid[3*i+2], border)
+	}
+
+	laneX := func(e *asciiEdge) int { return boxWidth + asciiLaneGap*(e.lane+1) - 1 }
+	for _, e := range edges {
+		a, b := span(e)
+		vertical := byte('|')
+		if e.weak {
+			vertical = ':'
+		}
+		for y := a; y <= b; y++ {
+			grid[y][laneX(e)] = vertical
+		}
+	}
+	for _, e := range edges {
+		horizontal := byte('-')
+		if e.weak {
+			horizontal = '.'
+		}
+		x := laneX(e)
+		for _, y := range []int{3*e.from + 2, 3 * e.to} {
+			for cx := boxWidth; cx < x; cx++ {
+				if c := grid[y][cx]; c == ' ' || c == '-' || c == '.' {
+					grid[y][cx] = horizontal
+				}
+			}
+			grid[y][x] = '+'
+		}
+		grid[3*e.to][boxWidth] = '<'
+	}
+
+	var sb strings.Builder
+	for _, line := range grid {
+		sb.WriteString(strings.TrimRight(string(line), " "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// asciiEdgeList is the fallback of GenerateASCII for large graphs.
+func (pa *PluginAnalyzer) asciiEdgeList(nodes []*Plugin) string {
+	var lines []string
+	for _, plugin := range nodes {
+		for _, edge := range pa.renderedEdges(plugin) {
+			target, ok := pa.Plugins[edge.Target]
+			if !ok || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+			arrow := "-->"
+			if edge.Kinds[0] != KindRequire {
+				arrow = "..>"
+			}
+			lines = append(lines, fmt.Sprintf("%s %s %s", plugin.FolderName, arrow, target.FolderName))
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BOMEntry is one external package of the bill of materials.
+type BOMEntry struct {
+	Package      string
+	Requirements []Requirement // constraint per requiring plugin, sorted by plugin
+	UsedBy       int
+	Resolved     string // locked version, empty without a lock file entry
+}
+
+// BillOfMaterials lists every external package required by an internal
+// plugin, sorted by name, with the constraints declaring it and the number
+// of plugins using it. locked maps package names to resolved versions and
+// may be nil.
+func (pa *PluginAnalyzer) BillOfMaterials(locked map[string]string) []BOMEntry {
+	var entries []BOMEntry
+	for pkg, reqs := range pa.requirements() {
+		if plugin, ok := pa.Plugins[pkg]; ok && !plugin.IsExternal {
+			continue
+		}
+		users := make(map[string]bool)
+		for _, req := range reqs {
+			users[req.Plugin] = true
+		}
+		sort.Slice(reqs, func(i, j int) bool { return reqs[i].Plugin < reqs[j].Plugin })
+		entries = append(entries, BOMEntry{Package: pkg, Requirements: reqs, UsedBy: len(users), Resolved: locked[pkg]})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Package < entries[j].Package })
+	return entries
+}
+
+// constraintList renders the requirements as "PluginA: ^1.0; PluginB: ^1.2".
+func (e BOMEntry) constraintList() string {
+	var parts []string
+	for _, req := range e.Requirements {
+		parts = append(parts, fmt.Sprintf("%s: %s", req.Plugin, req.Constraint))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GenerateBOMCSV returns the bill of materials as CSV with the columns
+// package, constraints, used_by and resolved_version.
+func GenerateBOMCSV(entries []BOMEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"package", "constraints", "used_by", "resolved_version"})
+	for _, e := range entries {
+		w.Write([]string{e.Package, e.constraintList(), fmt.Sprint(e.UsedBy), e.Resolved})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+type cycloneDXBOM struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	Group      string              `json:"group,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl"`
+	Properties []cycloneDXProperty `json:"properties"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// GenerateBOMJSON returns the bill of materials as a CycloneDX 1.5 JSON
+// document. Constraints and usage counts, which CycloneDX has no fields
+// for, are component properties prefixed with "sw6-plugin-analyzer:".
+func GenerateBOMJSON(entries []BOMEntry) ([]byte, error) {
+	bom := cycloneDXBOM{BOMFormat: "CycloneDX", SpecVersion: "1.5", Version: 1, Components: []cycloneDXComponent{}}
+	for _, e := range entries {
+		group, name, _ := strings.Cut(e.Package, "/")
+		purl := "pkg:composer/" + e.Package
+		if e.Resolved != "" {
+			purl += "@" + e.Resolved
+		}
+		bom.Components = append(bom.Components, cycloneDXComponent{
+			Type:    "library",
+			Group:   group,
+			Name:    name,
+			Version: e.Resolved,
+			PURL:    purl,
+			Properties: []cycloneDXProperty{
+				{Name: "sw6-plugin-analyzer:constraints", Value: e.constraintList()},
+				{Name: "sw6-plugin-analyzer:used-by", Value: fmt.Sprint(e.UsedBy)},
+			},
+		})
+	}
+
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// shopwareBundles returns the bundle references of an extra.shopware-bundles
+// section: a list of names, or an object whose entries are named by their
+// "name" field or, lacking one, by their key. References are composer
+// package names or plugin folder names. Malformed sections yield none.
+func shopwareBundles(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(raw, &names); err == nil {
+		return names
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil
+	}
+	for key, value := range entries {
+		var bundle struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(value, &bundle) == nil && bundle.Name != "" {
+			key = bundle.Name
+		}
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addBundleDependencies adds a bundle edge from plugin to every scanned
+// plugin its extra.shopware-bundles section refers to, by composer or
+// folder name. References to anything else are ignored, since bundles
+// aren't installed as packages of their own.
+func (pa *PluginAnalyzer) addBundleDependencies(plugin *Plugin) {
+	for _, ref := range plugin.Bundles {
+		target, ok := pa.Plugins[pa.canonicalName(normalizePackageName(ref))]
+		if !ok || target.IsExternal {
+			target = pa.PluginByFolder(ref)
+		}
+		if target == nil {
+			pa.debugf("Bundle %s of %s is not a scanned plugin, ignored", ref, plugin.Name)
+			continue
+		}
+		if target.Name == plugin.Name || pa.excluded[target.Name] || hasDependency(plugin, target.Name, KindBundle) {
+			continue
+		}
+		plugin.Dependencies = append(plugin.Dependencies, Dependency{Name: target.Name, Kind: KindBundle})
+		pa.debugf("Edge %s -> %s (%s)", plugin.Name, target.Name, KindBundle)
+	}
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheFile is where parsed composer.json files are cached between
+// runs, relative to the working directory.
+const DefaultCacheFile = ".plugin-analyzer-cache.json"
+
+// cacheVersion is bumped whenever ComposerJSON gains a field, so entries
+// parsed by an older release are read again.
+const cacheVersion = 6
+
+// cacheEntry is a parsed composer.json together with the size and
+// modification time of the file it was parsed from.
+type cacheEntry struct {
+	ModTime  time.Time    `json:"modTime"`
+	Size     int64        `json:"size"`
+	Encoding string       `json:"encoding,omitempty"` // non-UTF-8 encoding the file was converted from
+	Composer ComposerJSON `json:"composer"`
+}
+
+// scanCache holds the composer.json files parsed during a scan, keyed by
+// absolute path. An entry is only used while the file's size and
+// modification time are unchanged.
+type scanCache struct {
+	Version int                   `json:"version"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+// loadScanCache reads the cache file at path. A missing or unreadable file
+// yields an empty cache, as does an empty path.
+func (pa *PluginAnalyzer) loadScanCache(path string) *scanCache {
+	cache := &scanCache{Version: cacheVersion, Entries: make(map[string]cacheEntry)}
+	if path == "" {
+		return cache
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, cache); err != nil || cache.Entries == nil {
+		pa.warnf("ignoring unreadable scan cache %s", path)
+		cache.Entries = make(map[string]cacheEntry)
+	}
+	if cache.Version != cacheVersion {
+		cache.Version, cache.Entries = cacheVersion, make(map[string]cacheEntry)
+	}
+	return cache
+}
+
+func cacheKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// get returns the cached entry for a composer.json if the file is unchanged.
+func (c *scanCache) get(path string) (cacheEntry, bool) {
+	entry, ok := c.Entries[cacheKey(path)]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.ModTime().Equal(entry.ModTime) || info.Size() != entry.Size {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// put records a freshly parsed composer.json and returns its entry.
+func (c *scanCache) put(path string, composer ComposerJSON, encoding string) cacheEntry {
+	entry := cacheEntry{Encoding: encoding, Composer: composer}
+	if info, err := os.Stat(path); err == nil {
+		entry.ModTime, entry.Size = info.ModTime(), info.Size()
+		c.Entries[cacheKey(path)] = entry
+	}
+	return entry
+}
+
+// save writes the cache to path, leaving out files that no longer exist so
+// removed plugin folders don't linger.
+func (c *scanCache) save(path string) error {
+	for key := range c.Entries {
+		if _, err := os.Stat(key); err != nil {
+			delete(c.Entries, key)
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan cache: %w", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write scan cache: %w", err)
+	}
+	return nil
+}
+
+// readComposer reads a composer.json, converting it to UTF-8, and parses it.
+// It returns the encoding the file was converted from, if any. parseErr
+// tells whether a failure happened while parsing rather than reading.
+func (pa *PluginAnalyzer) readComposer(path string) (composer ComposerJSON, encoding string, parseErr bool, err error) {
+	pa.debugf("Reading %s", path)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return composer, "", false, err
+	}
+	return parseComposer(data)
+}
+
+// parseComposer converts the contents of a composer.json to UTF-8 and
+// parses them, with the results of readComposer.
+func parseComposer(data []byte) (composer ComposerJSON, encoding string, parseErr bool, err error) {
+	data, encoding, err = ToUTF8(data)
+	if err != nil {
+		return composer, "", false, err
+	}
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return composer, encoding, true, jsonErrorPosition(data, err)
+	}
+	return composer, encoding, false, nil
+}
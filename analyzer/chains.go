@@ -0,0 +1,101 @@
+package analyzer
+
+import "sort"
+
+// CollapseChains returns a copy of the analyzer in which every maximal chain
+// of pass-through plugins, i.e. internal plugins with exactly one dependent
+// and exactly one dependency in the rendered graph, is replaced by a single
+// edge from the plugin before the chain to the plugin after it. The hidden
+// plugins are recorded in the edge's Via field, which the renderers show as
+// a label. Reachability between the remaining plugins is unchanged.
+func (pa *PluginAnalyzer) CollapseChains() *PluginAnalyzer {
+	visible := func(name string) bool {
+		plugin, ok := pa.Plugins[name]
+		return ok && (!plugin.IsExternal || pa.ShowExternalDeps)
+	}
+
+	in := make(map[string]map[string]bool)
+	out := make(map[string]map[string]bool)
+	for name := range pa.Plugins {
+		if !visible(name) {
+			continue
+		}
+		for _, dep := range pa.Plugins[name].Dependencies {
+			if !visible(dep.Name) {
+				continue
+			}
+			if out[name] == nil {
+				out[name] = make(map[string]bool)
+			}
+			if in[dep.Name] == nil {
+				in[dep.Name] = make(map[string]bool)
+			}
+			out[name][dep.Name] = true
+			in[dep.Name][name] = true
+		}
+	}
+
+	passThrough := func(name string) bool {
+		return !pa.Plugins[name].IsExternal && len(in[name]) == 1 && len(out[name]) == 1 && !out[name][name]
+	}
+	next := func(name string) string {
+		for target := range out[name] {
+			return target
+		}
+		return ""
+	}
+
+	var names []string
+	for name := range pa.Plugins {
+		if visible(name) && !passThrough(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	hidden := make(map[string]bool)
+	collapsed := make(map[string][]Dependency)
+	for _, name := range names {
+		for _, dep := range pa.Plugins[name].Dependencies {
+			if !visible(dep.Name) || !passThrough(dep.Name) {
+				continue
+			}
+
+			// Follow the chain; a pass-through plugin has a single dependent,
+			// so each chain is entered from exactly one edge.
+			edge := Dependency{Kind: dep.Kind, Optional: dep.Optional}
+			current := dep.Name
+			for passThrough(current) {
+				edge.Via = append(edge.Via, current)
+				hidden[current] = true
+				target := next(current)
+				for _, d := range pa.Plugins[current].Dependencies {
+					if d.Name == target {
+						if kindOrder[d.Kind] > kindOrder[edge.Kind] {
+							edge.Kind = d.Kind
+						}
+						edge.Optional = edge.Optional || d.Optional
+						break
+					}
+				}
+				current = target
+			}
+			edge.Name = current
+			collapsed[name] = append(collapsed[name], edge)
+		}
+	}
+
+	keep := make(map[string]bool)
+	for name := range pa.Plugins {
+		if !hidden[name] {
+			keep[name] = true
+		}
+	}
+	sub := pa.subset(keep)
+	// Hidden chain members still use their packages, they just aren't drawn.
+	sub.ExternalDepsCount, sub.externalUsers = pa.ExternalDepsCount, pa.externalUsers
+	for name, edges := range collapsed {
+		sub.Plugins[name].Dependencies = append(sub.Plugins[name].Dependencies, edges...)
+	}
+	return sub
+}
@@ -0,0 +1,21 @@
+package analyzer
+
+// checkRules are the findings -check treats as problems.
+var checkRules = map[string]bool{
+	RuleCircularDependency: true,
+	RuleVersionConflict:    true,
+	RuleMissingInternal:    true,
+}
+
+// CheckProblems returns the findings -check fails on: dependency cycles,
+// conflicting version constraints and missing internal plugins (the latter
+// only with InternalPrefixes), in the order of Findings.
+func (pa *PluginAnalyzer) CheckProblems() []Finding {
+	var problems []Finding
+	for _, f := range pa.Findings() {
+		if checkRules[f.RuleID] {
+			problems = append(problems, f)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ungroupedCluster is the cluster label for plugins lacking the metadata
+// field selected with -cluster-by meta:<field>.
+const ungroupedCluster = "ungrouped"
+
+// ParseClusterBy validates a -cluster-by value. Accepted values are
+// "vendor" and "meta:<field>".
+func ParseClusterBy(value string) error {
+	if value == "" || value == "vendor" {
+		return nil
+	}
+	if field, ok := strings.CutPrefix(value, "meta:"); ok && field != "" {
+		return nil
+	}
+	return fmt.Errorf("invalid -cluster-by value %q: expected \"vendor\" or \"meta:<field>\"", value)
+}
+
+// vendorOf returns the vendor prefix of a composer package name.
+func vendorOf(name string) string {
+	vendor, _, _ := strings.Cut(name, "/")
+	return vendor
+}
+
+// clusterKey returns the cluster a plugin belongs to under the configured
+// ClusterBy setting. The second return value is false when the plugin
+// should be rendered outside of any cluster.
+func (pa *PluginAnalyzer) clusterKey(plugin *Plugin) (string, bool) {
+	if pa.ClusterBy == "vendor" {
+		return vendorOf(plugin.Name), true
+	}
+
+	field, ok := strings.CutPrefix(pa.ClusterBy, "meta:")
+	if !ok {
+		return "", false
+	}
+	if plugin.IsExternal {
+		return "", false
+	}
+	if value := plugin.Metadata[field]; value != "" {
+		return value, true
+	}
+	return ungroupedCluster, true
+}
+
+// writeDOTNodes writes the given node statements, grouped into
+// "cluster_<key>" subgraphs when clustering is enabled.
+func (pa *PluginAnalyzer) writeDOTNodes(w io.Writer, plugins []*Plugin, nodeLine func(*Plugin) string) {
+	if pa.ClusterBy == "" {
+		for _, plugin := range plugins {
+			io.WriteString(w, nodeLine(plugin))
+		}
+		return
+	}
+
+	clusters := make(map[string][]*Plugin)
+	for _, plugin := range plugins {
+		key, ok := pa.clusterKey(plugin)
+		if !ok {
+			io.WriteString(w, nodeLine(plugin))
+			continue
+		}
+		clusters[key] = append(clusters[key], plugin)
+	}
+
+	keys := make([]string, 0, len(clusters))
+	for key := range clusters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		io.WriteString(w, fmt.Sprintf("    subgraph \"cluster_%s\" {\n", escapeDOT(key)))
+		io.WriteString(w, fmt.Sprintf("        label=\"%s\";\n", escapeDOT(key)))
+		io.WriteString(w, "        style=\"rounded,dashed\";\n")
+		for _, plugin := range clusters[key] {
+			io.WriteString(w, "    "+nodeLine(plugin))
+		}
+		io.WriteString(w, "    }\n")
+	}
+}
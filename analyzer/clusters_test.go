@@ -0,0 +1,28 @@
+package analyzer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestClusterKeyIsEscaped(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a"}`,
+	})
+	pa.ClusterBy = "meta:team"
+	pa.Plugins["v/a"].Metadata = map[string]string{"team": `Ops "core" \ infra`}
+
+	var dot bytes.Buffer
+	if err := pa.GenerateDOT(&dot); err != nil {
+		t.Fatalf("GenerateDOT: %v", err)
+	}
+	for _, want := range []string{
+		`subgraph "cluster_Ops \"core\" \\ infra" {`,
+		`label="Ops \"core\" \\ infra";`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("DOT lacks %s:\n%s", want, dot.String())
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CollapsedVendor is an aggregate node standing for all plugins of a vendor.
+type CollapsedVendor struct {
+	Vendor  string
+	Node    string   // composer name of the aggregate node, e.g. "acme/*"
+	Plugins []string // folder names of the collapsed plugins, sorted
+}
+
+// CollapseVendors returns a copy of the analyzer in which the plugins of
+// each of the given vendors are replaced by one aggregate node. Edges of the
+// collapsed plugins are rerouted to and from that node, parallel edges of
+// the same kind are merged and edges within a vendor are dropped. The
+// aggregate is internal unless all of its plugins are external packages.
+// Vendors without plugins are left out of the returned list.
+func (pa *PluginAnalyzer) CollapseVendors(vendors []string) (*PluginAnalyzer, []CollapsedVendor) {
+	collapse := make(map[string]bool)
+	for _, vendor := range vendors {
+		collapse[normalizePackageName(vendor)] = true
+	}
+
+	nodeOf := make(map[string]string) // composer name -> aggregate node
+	members := make(map[string][]*Plugin)
+	for _, plugin := range pa.sortedPlugins() {
+		if vendor := vendorOf(plugin.Name); collapse[vendor] {
+			nodeOf[plugin.Name] = vendor + "/*"
+			members[vendor] = append(members[vendor], plugin)
+		}
+	}
+	mapped := func(name string) string {
+		if node, ok := nodeOf[name]; ok {
+			return node
+		}
+		return name
+	}
+
+	collapsed := *pa
+	collapsed.Plugins = make(map[string]*Plugin)
+	var result []CollapsedVendor
+	for vendor, plugins := range members {
+		node := &Plugin{Name: vendor + "/*", FolderName: vendor + "/*", IsExternal: true}
+		entry := CollapsedVendor{Vendor: vendor, Node: node.Name}
+		for _, plugin := range plugins {
+			node.IsExternal = node.IsExternal && plugin.IsExternal
+			node.Collapsed = append(node.Collapsed, plugin.FolderName)
+			entry.Plugins = append(entry.Plugins, plugin.FolderName)
+		}
+		collapsed.Plugins[node.Name] = node
+		result = append(result, entry)
+	}
+	for name, plugin := range pa.Plugins {
+		if _, ok := nodeOf[name]; !ok {
+			copied := *plugin
+			copied.Dependencies = nil
+			collapsed.Plugins[name] = &copied
+		}
+	}
+
+	for _, plugin := range pa.sortedPlugins() {
+		from := collapsed.Plugins[mapped(plugin.Name)]
+		for _, dep := range plugin.Dependencies {
+			to := mapped(dep.Name)
+			if to == from.Name || hasDependency(from, to, dep.Kind) {
+				continue
+			}
+			dep.Name = to
+			from.Dependencies = append(from.Dependencies, dep)
+		}
+	}
+	for _, plugin := range collapsed.Plugins {
+		sortDependencies(plugin)
+	}
+
+	collapsed.ExternalDepsCount = make(map[string]int)
+	collapsed.externalUsers = make(map[string]map[string]bool)
+	for dep, users := range pa.externalUsers {
+		for user := range users {
+			if target, ok := collapsed.Plugins[mapped(dep)]; !ok || target.IsExternal {
+				collapsed.countExternalUse(mapped(dep), collapsed.Plugins[mapped(user)])
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Vendor < result[j].Vendor })
+	return &collapsed, result
+}
+
+// collapsedLabel describes an aggregate node of CollapseVendors.
+func collapsedLabel(plugin *Plugin) string {
+	return fmt.Sprintf("%d plugins collapsed", len(plugin.Collapsed))
+}
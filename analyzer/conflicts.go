@@ -0,0 +1,80 @@
+package analyzer
+
+import "sort"
+
+// Requirement is a version constraint one plugin declares on a package.
+type Requirement struct {
+	Plugin     string
+	Constraint string
+}
+
+// ConstraintConflict describes a package whose requirements across plugins
+// cannot all be satisfied by a single version.
+type ConstraintConflict struct {
+	Package      string
+	Requirements []Requirement
+	RootConflict string // conflict constraint of the root composer.json that took part, if any
+}
+
+// requirements returns the constraints internal plugins declare per package,
+// keyed by package name. require-dev entries are included with IncludeDev.
+// Packages the root composer.json replaces are left out.
+func (pa *PluginAnalyzer) requirements() map[string][]Requirement {
+	reqs := make(map[string][]Requirement)
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		sections := []map[string]string{plugin.Require}
+		if pa.IncludeDev {
+			sections = append(sections, plugin.RequireDev)
+		}
+		for _, section := range sections {
+			for dep, c := range section {
+				if !pa.isTrackedPackage(dep) {
+					continue
+				}
+				dep = pa.canonicalName(dep)
+				if pa.replacedByRoot(dep) {
+					continue
+				}
+				reqs[dep] = append(reqs[dep], Requirement{Plugin: plugin.FolderName, Constraint: c})
+			}
+		}
+	}
+	return reqs
+}
+
+// ConflictingConstraints returns the packages required by several plugins
+// with version constraints that have no version in common, sorted by package
+// name. A conflict entry in the root composer.json counts as one more
+// constraint, so a single requirement can conflict with it. Constraints that
+// cannot be parsed are ignored.
+func (pa *PluginAnalyzer) ConflictingConstraints() []ConstraintConflict {
+	var conflicts []ConstraintConflict
+	for pkg, reqs := range pa.requirements() {
+		allowed, rootConflict, hasRoot := pa.rootAllowed(pkg)
+		if len(reqs) < 2 && !hasRoot {
+			continue
+		}
+
+		combined := anyVersion
+		if hasRoot {
+			combined = allowed
+		}
+		for _, req := range reqs {
+			c, err := parseConstraint(req.Constraint)
+			if err != nil {
+				continue
+			}
+			combined = combined.intersect(c)
+		}
+		if combined.satisfiable() {
+			continue
+		}
+
+		sort.Slice(reqs, func(i, j int) bool { return reqs[i].Plugin < reqs[j].Plugin })
+		conflicts = append(conflicts, ConstraintConflict{Package: pkg, Requirements: reqs, RootConflict: rootConflict})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Package < conflicts[j].Package })
+	return conflicts
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CouplingRow holds the direct and transitive dependency and dependent
+// counts of one internal plugin.
+type CouplingRow struct {
+	FolderName           string
+	Dependencies         int
+	TransitiveDeps       int
+	Dependents           int
+	TransitiveDependents int
+}
+
+// CouplingColumns are the columns CouplingTable can sort by. Name sorts
+// ascending, the counts descending.
+var CouplingColumns = []string{"name", "deps", "transitive-deps", "dependents", "transitive-dependents"}
+
+// IsCouplingColumn reports whether CouplingTable can sort by column.
+func IsCouplingColumn(column string) bool {
+	for _, c := range CouplingColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitiveDependents returns the sorted composer names of all internal
+// plugins that reach the named plugin, excluding the plugin itself.
+func (pa *PluginAnalyzer) TransitiveDependents(name string) []string {
+	visited := map[string]bool{name: true}
+	var closure []string
+
+	var visit func(current string)
+	visit = func(current string) {
+		for _, dependent := range pa.directDependents(current) {
+			if visited[dependent] {
+				continue
+			}
+			visited[dependent] = true
+			closure = append(closure, dependent)
+			visit(dependent)
+		}
+	}
+	visit(name)
+
+	sort.Strings(closure)
+	return closure
+}
+
+// CouplingTable returns one row per internal plugin, sorted by the given
+// column with ties broken by folder name.
+func (pa *PluginAnalyzer) CouplingTable(sortBy string) ([]CouplingRow, error) {
+	var key func(r CouplingRow) int
+	switch sortBy {
+	case "name":
+	case "deps":
+		key = func(r CouplingRow) int { return r.Dependencies }
+	case "transitive-deps":
+		key = func(r CouplingRow) int { return r.TransitiveDeps }
+	case "dependents":
+		key = func(r CouplingRow) int { return r.Dependents }
+	case "transitive-dependents":
+		key = func(r CouplingRow) int { return r.TransitiveDependents }
+	default:
+		return nil, fmt.Errorf("unknown sort column %q (want one of %s)", sortBy, strings.Join(CouplingColumns, ", "))
+	}
+
+	var rows []CouplingRow
+	for _, name := range pa.InternalPluginNames() {
+		rows = append(rows, CouplingRow{
+			FolderName:           pa.Plugins[name].FolderName,
+			Dependencies:         len(pa.internalDependencies(pa.Plugins[name])),
+			TransitiveDeps:       len(pa.TransitiveDependencies(name)),
+			Dependents:           len(pa.directDependents(name)),
+			TransitiveDependents: len(pa.TransitiveDependents(name)),
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if key != nil && key(rows[i]) != key(rows[j]) {
+			return key(rows[i]) > key(rows[j])
+		}
+		return rows[i].FolderName < rows[j].FolderName
+	})
+	return rows, nil
+}
+
+// FormatCouplingTable returns rows as a column-aligned table, each line
+// indented by two spaces.
+func FormatCouplingTable(rows []CouplingRow) string {
+	width := len("Plugin")
+	for _, r := range rows {
+		width = max(width, len(r.FolderName))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  %-*s  %5s  %10s  %10s  %10s\n", width, "Plugin", "Deps", "Trans.Deps", "Dependents", "Trans.Dep.")
+	for _, r := range rows {
+		fmt.Fprintf(&sb, "  %-*s  %5d  %10d  %10d  %10d\n", width, r.FolderName,
+			r.Dependencies, r.TransitiveDeps, r.Dependents, r.TransitiveDependents)
+	}
+	return sb.String()
+}
+
+// Dependents returns the sorted folder names of the internal plugins that
+// require the plugin given by composer or folder name, or with transitive
+// also those requiring it through other plugins. It fails if there is no
+// such internal plugin.
+func (pa *PluginAnalyzer) Dependents(name string, transitive bool) ([]string, error) {
+	plugin := pa.FindPlugin(name)
+	if plugin == nil {
+		return nil, fmt.Errorf("unknown plugin %q", name)
+	}
+
+	names := pa.directDependents(plugin.Name)
+	if transitive {
+		names = pa.TransitiveDependents(plugin.Name)
+	}
+	folders := make([]string, 0, len(names))
+	for _, n := range names {
+		folders = append(folders, pa.Plugins[n].FolderName)
+	}
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// ForbiddenDependent is an internal plugin depending on a plugin nothing may
+// depend on.
+type ForbiddenDependent struct {
+	Plugin string // folder of the depending plugin
+	Target string // folder of the forbidden plugin
+}
+
+// ForbiddenDependents returns the direct dependents of the named plugins
+// (folder or composer names), sorted by target and plugin, and the names
+// that match no scanned plugin.
+func (pa *PluginAnalyzer) ForbiddenDependents(targets []string) ([]ForbiddenDependent, []string) {
+	var forbidden []ForbiddenDependent
+	var unknown []string
+	for _, name := range targets {
+		target := pa.FindPlugin(name)
+		if target == nil {
+			unknown = append(unknown, name)
+			continue
+		}
+		for _, dependent := range pa.directDependents(target.Name) {
+			forbidden = append(forbidden, ForbiddenDependent{Plugin: pa.Plugins[dependent].FolderName, Target: target.FolderName})
+		}
+	}
+
+	sort.Slice(forbidden, func(i, j int) bool {
+		if forbidden[i].Target != forbidden[j].Target {
+			return forbidden[i].Target < forbidden[j].Target
+		}
+		return forbidden[i].Plugin < forbidden[j].Plugin
+	})
+	return forbidden, unknown
+}
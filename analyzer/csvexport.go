@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GenerateCSV returns the dependency edges as CSV with the columns
+// from_folder, from_name, to_folder, to_name and is_external, sorted by
+// from_name and then to_name. Edges of different kinds between the same
+// plugins are one row. External packages are included with -show-external
+// only, as in the other formats.
+func (pa *PluginAnalyzer) GenerateCSV() (string, error) {
+	type edge struct{ from, to *Plugin }
+	var edges []edge
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		seen := make(map[string]bool)
+		for _, dep := range plugin.Dependencies {
+			target, ok := pa.Plugins[dep.Name]
+			if !ok || seen[dep.Name] || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+			seen[dep.Name] = true
+			edges = append(edges, edge{plugin, target})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].from.Name != edges[j].from.Name {
+			return edges[i].from.Name < edges[j].from.Name
+		}
+		return edges[i].to.Name < edges[j].to.Name
+	})
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Write([]string{"from_folder", "from_name", "to_folder", "to_name", "is_external"})
+	for _, e := range edges {
+		w.Write([]string{e.from.FolderName, e.from.Name, e.to.FolderName, e.to.Name, strconv.FormatBool(e.to.IsExternal)})
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
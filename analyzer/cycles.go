@@ -0,0 +1,171 @@
+package analyzer
+
+import "sort"
+
+// DetectCycles returns every dependency cycle between internal plugins that
+// a depth-first search finds, each as an ordered slice of composer names
+// starting at the node where the cycle was entered. The closing edge back to
+// the first element is implied. Plugins and their dependencies are visited in
+// sorted order so the result is stable across runs.
+func (pa *PluginAnalyzer) DetectCycles() [][]string {
+	const (
+		unvisited = iota
+		inStack
+		done
+	)
+
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = inStack
+		stack = append(stack, name)
+
+		for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+			switch state[dep] {
+			case unvisited:
+				visit(dep)
+			case inStack:
+				for i := len(stack) - 1; i >= 0; i-- {
+					if stack[i] == dep {
+						cycle := make([]string, len(stack)-i)
+						copy(cycle, stack[i:])
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+
+	for _, name := range pa.InternalPluginNames() {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return cycles
+}
+
+// InternalPluginNames returns the composer names of all internal plugins in
+// sorted order.
+func (pa *PluginAnalyzer) InternalPluginNames() []string {
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if !plugin.IsExternal {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CyclesContaining returns every cycle between internal plugins that passes
+// through the named plugin, each as an ordered slice of composer names
+// starting with that plugin. Unlike DetectCycles, which reports one cycle
+// per back edge it finds, this lists all distinct cycles through the plugin.
+func (pa *PluginAnalyzer) CyclesContaining(name string) [][]string {
+	start, ok := pa.Plugins[name]
+	if !ok || start.IsExternal {
+		return nil
+	}
+
+	onPath := map[string]bool{name: true}
+	path := []string{name}
+	var cycles [][]string
+
+	var visit func(current string)
+	visit = func(current string) {
+		for _, dep := range pa.internalDependencies(pa.Plugins[current]) {
+			if dep == name {
+				cycle := make([]string, len(path))
+				copy(cycle, path)
+				cycles = append(cycles, cycle)
+				continue
+			}
+			if onPath[dep] {
+				continue
+			}
+			onPath[dep] = true
+			path = append(path, dep)
+			visit(dep)
+			path = path[:len(path)-1]
+			delete(onPath, dep)
+		}
+	}
+	visit(name)
+
+	return cycles
+}
+
+// cycleNodes returns the composer names of the plugins on a cycle found by
+// DetectCycles, in sorted order.
+func (pa *PluginAnalyzer) cycleNodes() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, cycle := range pa.DetectCycles() {
+		for _, name := range cycle {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// components returns the strongly connected components of the internal
+// plugins, found with Tarjan's algorithm, as the index of every plugin's
+// component keyed by composer name. Plugins on a common cycle share an
+// index; every other plugin has one of its own.
+func (pa *PluginAnalyzer) components() map[string]int {
+	index := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	component := make(map[string]int)
+	var stack []string
+	next, count := 0, 0
+
+	var visit func(name string)
+	visit = func(name string) {
+		index[name], lowlink[name] = next, next
+		next++
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+			if _, seen := index[dep]; !seen {
+				visit(dep)
+				lowlink[name] = min(lowlink[name], lowlink[dep])
+			} else if onStack[dep] {
+				lowlink[name] = min(lowlink[name], index[dep])
+			}
+		}
+
+		if lowlink[name] == index[name] {
+			for {
+				member := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[member] = false
+				component[member] = count
+				if member == name {
+					break
+				}
+			}
+			count++
+		}
+	}
+
+	for _, name := range pa.InternalPluginNames() {
+		if _, seen := index[name]; !seen {
+			visit(name)
+		}
+	}
+	return component
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectCycles(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		composers map[string]string
+		want      [][]string
+	}{
+		{
+			name: "none",
+			composers: map[string]string{
+				"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+				"B": `{"name": "v/b"}`,
+			},
+		},
+		{
+			name: "two plugins",
+			composers: map[string]string{
+				"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+				"B": `{"name": "v/b", "require": {"v/a": "*"}}`,
+			},
+			want: [][]string{{"v/a", "v/b"}},
+		},
+		{
+			name: "three plugins",
+			composers: map[string]string{
+				"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+				"B": `{"name": "v/b", "require": {"v/c": "*"}}`,
+				"C": `{"name": "v/c", "require": {"v/a": "*", "x/external": "*"}}`,
+				"D": `{"name": "v/d", "require": {"v/a": "*"}}`,
+			},
+			want: [][]string{{"v/a", "v/b", "v/c"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			pa, _ := scanFixture(t, tt.composers)
+			if got := pa.DetectCycles(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectCycles() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelfRequirementIsNoCycle(t *testing.T) {
+	pa, logs := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/a": "*"}}`,
+	})
+	if cycles := pa.DetectCycles(); len(cycles) > 0 {
+		t.Errorf("DetectCycles() = %q, want none", cycles)
+	}
+	if !strings.Contains(logs.String(), "self-dependency: A lists its own package v/a") {
+		t.Errorf("no self-dependency warning; log:\n%s", logs)
+	}
+}
+
+func TestFormatCycle(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b", "require": {"v/a": "*"}}`,
+	})
+	if got, want := pa.FormatCycle([]string{"v/a", "v/b"}), "A → B → A"; got != want {
+		t.Errorf("FormatCycle() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// cypherString quotes s as a Cypher string literal.
+func cypherString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// GenerateCypher returns Neo4j Cypher statements that recreate the graph:
+// a MERGE per node with the properties name, folder, external and version,
+// and a MERGE per dependency creating a DEPENDS_ON relationship with kind,
+// constraint and optional properties. MERGE makes loading the file twice
+// harmless. Statements are sorted so the file diffs cleanly.
+func (pa *PluginAnalyzer) GenerateCypher() string {
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("CREATE CONSTRAINT plugin_name IF NOT EXISTS FOR (p:Plugin) REQUIRE p.name IS UNIQUE;\n\n")
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		sb.WriteString(fmt.Sprintf("MERGE (p:Plugin {name: %s}) SET p.folder = %s, p.external = %t, p.version = %s;\n",
+			cypherString(plugin.Name), cypherString(plugin.FolderName), plugin.IsExternal, cypherString(plugin.Version)))
+	}
+
+	sb.WriteString("\n")
+	for _, name := range names {
+		deps := append([]Dependency(nil), pa.Plugins[name].Dependencies...)
+		sort.Slice(deps, func(i, j int) bool {
+			if deps[i].Name != deps[j].Name {
+				return deps[i].Name < deps[j].Name
+			}
+			return kindOrder[deps[i].Kind] < kindOrder[deps[j].Kind]
+		})
+		for _, dep := range deps {
+			if target, ok := pa.Plugins[dep.Name]; !ok || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("MATCH (a:Plugin {name: %s}), (b:Plugin {name: %s}) MERGE (a)-[d:DEPENDS_ON {kind: %s}]->(b) SET d.constraint = %s, d.optional = %t;\n",
+				cypherString(name), cypherString(dep.Name), cypherString(string(dep.Kind)), cypherString(dep.Constraint), dep.Optional))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Change kinds of an ExternalDelta.
+const (
+	DeltaAdded     = "added"
+	DeltaRemoved   = "removed"
+	DeltaIncreased = "increased"
+	DeltaDecreased = "decreased"
+)
+
+// ExternalDelta is an external package whose usage count differs from the
+// baseline.
+type ExternalDelta struct {
+	Package  string
+	Change   string
+	Baseline int
+	Current  int
+}
+
+// GenerateExternalCounts returns ExternalDepsCount as indented JSON, the
+// baseline format read by LoadExternalBaseline.
+func (pa *PluginAnalyzer) GenerateExternalCounts() ([]byte, error) {
+	data, err := json.MarshalIndent(pa.ExternalDepsCount, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// LoadExternalBaseline reads a JSON object mapping external package names
+// to the number of plugins using them.
+func LoadExternalBaseline(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline map[string]int
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// ExternalDeltas compares ExternalDepsCount with a baseline and returns only
+// the packages whose usage changed, sorted by package name.
+func (pa *PluginAnalyzer) ExternalDeltas(baseline map[string]int) []ExternalDelta {
+	var deltas []ExternalDelta
+	for pkg, current := range pa.ExternalDepsCount {
+		before, known := baseline[pkg]
+		switch {
+		case !known:
+			deltas = append(deltas, ExternalDelta{Package: pkg, Change: DeltaAdded, Current: current})
+		case current > before:
+			deltas = append(deltas, ExternalDelta{Package: pkg, Change: DeltaIncreased, Baseline: before, Current: current})
+		case current < before:
+			deltas = append(deltas, ExternalDelta{Package: pkg, Change: DeltaDecreased, Baseline: before, Current: current})
+		}
+	}
+	for pkg, before := range baseline {
+		if _, ok := pa.ExternalDepsCount[pkg]; !ok {
+			deltas = append(deltas, ExternalDelta{Package: pkg, Change: DeltaRemoved, Baseline: before})
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Package < deltas[j].Package
+	})
+	return deltas
+}
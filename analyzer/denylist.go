@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DeniedRequirement is a requirement on a package matched by the denylist.
+type DeniedRequirement struct {
+	Plugin  string // folder of the requiring plugin
+	Package string
+	Pattern string // the denylist entry that matched
+	Dev     bool   // required via require-dev
+}
+
+// LoadDenylist reads a denylist file with one package name or glob pattern
+// such as "abandoned/*" per line. Blank lines and text after "#" are ignored.
+func LoadDenylist(filename string) ([]string, error) {
+	return loadPackagePatterns(filename, "denylist")
+}
+
+// loadPackagePatterns reads a file of package names or glob patterns in the
+// denylist format; what names the file in errors.
+func loadPackagePatterns(filename, what string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		entry, _, _ := strings.Cut(scanner.Text(), "#")
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if _, err := path.Match(entry, ""); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid pattern %q", filename, line, entry)
+		}
+		patterns = append(patterns, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", what, err)
+	}
+	return patterns, nil
+}
+
+// deniedBy returns the first denylist pattern matching the package, or "".
+func (pa *PluginAnalyzer) deniedBy(pkg string) string {
+	pkg = strings.ToLower(pkg)
+	for _, pattern := range pa.Denylist {
+		if ok, _ := path.Match(pattern, pkg); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// DeniedRequirements returns every require (and, with IncludeDev,
+// require-dev) entry of the internal plugins that matches the denylist,
+// whether the package is internal or external. The result is sorted by
+// plugin and package.
+func (pa *PluginAnalyzer) DeniedRequirements() []DeniedRequirement {
+	if len(pa.Denylist) == 0 {
+		return nil
+	}
+
+	var denied []DeniedRequirement
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		check := func(section map[string]string, dev bool) {
+			for pkg := range section {
+				if pattern := pa.deniedBy(pkg); pattern != "" {
+					denied = append(denied, DeniedRequirement{Plugin: plugin.FolderName, Package: pkg, Pattern: pattern, Dev: dev})
+				}
+			}
+		}
+		check(plugin.Require, false)
+		if pa.IncludeDev {
+			check(plugin.RequireDev, true)
+		}
+	}
+
+	sort.Slice(denied, func(i, j int) bool {
+		if denied[i].Plugin != denied[j].Plugin {
+			return denied[i].Plugin < denied[j].Plugin
+		}
+		return denied[i].Package < denied[j].Package
+	})
+	return denied
+}
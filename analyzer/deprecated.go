@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// deprecatedKeyword is the composer keyword, and deprecatedMetaField the
+// plugin-meta.json field, that mark a plugin as deprecated.
+const (
+	deprecatedKeyword   = "deprecated"
+	deprecatedMetaField = "deprecated"
+)
+
+// DeprecatedDependency is a dependency on a plugin marked as deprecated.
+type DeprecatedDependency struct {
+	Plugin string // folder of the depending plugin
+	Target string // folder of the deprecated plugin
+}
+
+// isDeprecated reports whether composer keywords or plugin metadata mark a
+// plugin as deprecated. Any metadata value other than "", "false", "no"
+// and "0" counts, so the field can hold a reason like "use PluginX".
+func isDeprecated(keywords []string, metadata map[string]string) bool {
+	for _, k := range keywords {
+		if strings.EqualFold(k, deprecatedKeyword) {
+			return true
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(metadata[deprecatedMetaField])) {
+	case "", "false", "no", "0":
+		return false
+	}
+	return true
+}
+
+// DeprecatedDependencies returns the internal plugins still depending on a
+// deprecated plugin, sorted by dependent and target. Suggest edges don't
+// count.
+func (pa *PluginAnalyzer) DeprecatedDependencies() []DeprecatedDependency {
+	var deps []DeprecatedDependency
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		for _, dep := range pa.internalDependencies(plugin) {
+			if target := pa.Plugins[dep]; target.Deprecated {
+				deps = append(deps, DeprecatedDependency{Plugin: plugin.FolderName, Target: target.FolderName})
+			}
+		}
+	}
+
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Plugin != deps[j].Plugin {
+			return deps[i].Plugin < deps[j].Plugin
+		}
+		return deps[i].Target < deps[j].Target
+	})
+	return deps
+}
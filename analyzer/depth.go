@@ -0,0 +1,63 @@
+package analyzer
+
+import "fmt"
+
+// depthColors are the fill colors of the shallowest and the deepest plugins
+// with -color-by-depth; plugins in between get a blend of the two.
+var depthColors = [2][3]int{{0xd4, 0xe6, 0xf9}, {0xfd, 0xd0, 0xa2}}
+
+// Depths returns the longest distance of every internal plugin from a root,
+// a plugin no other internal plugin depends on, keyed by composer name.
+// Roots have depth 0. The plugins of a cycle count as a single node, so they
+// share one depth and the cycle neither adds to the depths below it nor makes
+// them grow without bound.
+func (pa *PluginAnalyzer) Depths() map[string]int {
+	component := pa.components()
+	dependents := make(map[int]map[int]bool)
+	for name, c := range component {
+		for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+			if d := component[dep]; d != c {
+				if dependents[d] == nil {
+					dependents[d] = make(map[int]bool)
+				}
+				dependents[d][c] = true
+			}
+		}
+	}
+
+	// The components form an acyclic graph, so the longest distance of each
+	// follows from the ones of the components depending on it.
+	componentDepths := make(map[int]int)
+	var depth func(c int) int
+	depth = func(c int) int {
+		if d, ok := componentDepths[c]; ok {
+			return d
+		}
+		d := 0
+		for dependent := range dependents[c] {
+			d = max(d, depth(dependent)+1)
+		}
+		componentDepths[c] = d
+		return d
+	}
+
+	depths := make(map[string]int, len(component))
+	for name, c := range component {
+		depths[name] = depth(c)
+	}
+	return depths
+}
+
+// depthColor returns the fill color for a plugin at depth out of maxDepth.
+func depthColor(depth, maxDepth int) string {
+	t := 0.0
+	if maxDepth > 0 {
+		t = float64(depth) / float64(maxDepth)
+	}
+	var rgb [3]int
+	for i := range rgb {
+		from, to := float64(depthColors[0][i]), float64(depthColors[1][i])
+		rgb[i] = int(from + (to-from)*t + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,35 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDepths(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*", "v/c": "*"}}`,
+		"B": `{"name": "v/b", "require": {"v/d": "*"}}`,
+		"C": `{"name": "v/c", "require": {"v/d": "*"}}`,
+		"D": `{"name": "v/d"}`,
+	})
+	want := map[string]int{"v/a": 0, "v/b": 1, "v/c": 1, "v/d": 2}
+	if got := pa.Depths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Depths() = %v, want %v", got, want)
+	}
+}
+
+func TestDepthsCollapseCycles(t *testing.T) {
+	// B and C require each other; as one node they sit between A and D.
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b", "require": {"v/c": "*"}}`,
+		"C": `{"name": "v/c", "require": {"v/b": "*", "v/d": "*"}}`,
+		"D": `{"name": "v/d"}`,
+		"E": `{"name": "v/e", "require": {"v/f": "*"}}`,
+		"F": `{"name": "v/f", "require": {"v/e": "*"}}`,
+	})
+	want := map[string]int{"v/a": 0, "v/b": 1, "v/c": 1, "v/d": 2, "v/e": 0, "v/f": 0}
+	if got := pa.Depths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Depths() = %v, want %v", got, want)
+	}
+}
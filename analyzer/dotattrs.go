@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DOTAttribute is a Graphviz attribute passed through from the command line.
+type DOTAttribute struct {
+	Key, Value string
+}
+
+var (
+	dotAttrKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// Values are written quoted; this keeps out anything that could end the
+	// quoted string or start HTML-like labels.
+	dotAttrValue = regexp.MustCompile(`^[A-Za-z0-9 _.,:;#%+\-/()*=]*$`)
+)
+
+// ParseDOTAttributes parses repeated key=value flag values into attributes,
+// rejecting keys that aren't plain DOT identifiers and values containing
+// quotes, brackets or other characters that could alter the DOT structure.
+func ParseDOTAttributes(flagName string, entries []string) ([]DOTAttribute, error) {
+	var attrs []DOTAttribute
+	for _, entry := range entries {
+		key, value, ok := strings.Cut(entry, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || !dotAttrKey.MatchString(key) {
+			return nil, fmt.Errorf("invalid -%s %q: expected key=value with a plain attribute name", flagName, entry)
+		}
+		if !dotAttrValue.MatchString(value) {
+			return nil, fmt.Errorf("invalid -%s %q: value contains characters not allowed in pass-through attributes", flagName, entry)
+		}
+		attrs = append(attrs, DOTAttribute{Key: key, Value: value})
+	}
+	return attrs, nil
+}
+
+// joinDOTAttributes renders attributes as a DOT attribute list body.
+func joinDOTAttributes(attrs []DOTAttribute) string {
+	parts := make([]string, len(attrs))
+	for i, a := range attrs {
+		parts[i] = fmt.Sprintf("%s=\"%s\"", a.Key, a.Value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,206 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kindOrder ranks dependency kinds from the strongest coupling to the weakest.
+var kindOrder = map[DependencyKind]int{
+	KindRequire:    0,
+	KindBundle:     1,
+	KindRequireDev: 2,
+	KindSuggest:    3,
+}
+
+// kindBadges is the short label used for a kind when it is merged into an
+// edge whose primary kind differs.
+var kindBadges = map[DependencyKind]string{
+	KindRequire:    "+require",
+	KindRequireDev: "+dev",
+	KindSuggest:    "+suggest",
+	KindBundle:     "+bundle",
+}
+
+// edgeGroup is a rendered edge to Target carrying one or more dependency kinds.
+// Kinds[0] is the primary kind and determines the line style.
+type edgeGroup struct {
+	Target    string
+	Kinds     []DependencyKind
+	Optional  bool     // every merged dependency is optional
+	Via       []string // plugins collapsed into this edge
+	Mismatch  bool     // constraint excludes the target's major version
+	Changed   bool     // touches a plugin in Changed
+	Dimmed    bool     // Changed or HighlightPath is set but this edge isn't highlighted
+	OnPath    bool     // a step of HighlightPath
+	Users     int      // plugins using the external target, sets the line width
+	Loose     bool     // external target with ConstrainInternalOnly, ignored for ranking
+	Tightness int      // with ColorByConstraint, constraintTightness of an internal edge
+}
+
+// maxUsagePenwidth caps the line width of edges to widely used packages.
+const maxUsagePenwidth = 5.0
+
+// usagePenwidth returns the Graphviz line width of an edge to an external
+// package used by the given number of plugins: 1 for a single user, growing
+// by half a point per additional user up to maxUsagePenwidth.
+func usagePenwidth(users int) float64 {
+	width := 1 + 0.5*float64(users-1)
+	if width > maxUsagePenwidth {
+		return maxUsagePenwidth
+	}
+	return width
+}
+
+// usersLabel describes how many plugins use an external package.
+func usersLabel(users int) string {
+	if users == 1 {
+		return "1 user"
+	}
+	return fmt.Sprintf("%d users", users)
+}
+
+// badge returns the label listing the secondary kinds of a merged edge, the
+// number of plugins collapsed into it and a version mismatch warning.
+func (g edgeGroup) badge() string {
+	var badges []string
+	if g.Mismatch {
+		badges = append(badges, "version mismatch")
+	}
+	for _, kind := range g.Kinds[1:] {
+		badges = append(badges, kindBadges[kind])
+	}
+	if len(g.Via) > 0 {
+		badges = append(badges, fmt.Sprintf("%d hidden", len(g.Via)))
+	}
+	return strings.Join(badges, " ")
+}
+
+// renderedEdges returns the edges of plugin to draw. When MergeEdges is set,
+// parallel edges to the same target are collapsed into a single group.
+func (pa *PluginAnalyzer) renderedEdges(plugin *Plugin) []edgeGroup {
+	var groups []edgeGroup
+	index := make(map[string]int)
+
+	for _, dep := range plugin.Dependencies {
+		group := edgeGroup{Target: dep.Name, Kinds: []DependencyKind{dep.Kind}, Optional: dep.Optional, Via: dep.Via, Mismatch: pa.majorMismatch(dep)}
+		if target, ok := pa.Plugins[dep.Name]; ok && target.IsExternal {
+			group.Users = pa.ExternalDepsCount[dep.Name]
+			group.Loose = pa.ConstrainInternalOnly
+		} else if ok && pa.ColorByConstraint {
+			group.Tightness = constraintTightness(dep.Constraint)
+		}
+		if len(pa.Changed) > 0 {
+			group.Changed = pa.Changed[plugin.Name] || pa.Changed[dep.Name]
+			group.Dimmed = !group.Changed
+		}
+		if len(pa.HighlightPath) > 0 {
+			group.OnPath = pa.highlightedEdge(plugin.Name, dep.Name)
+			group.Dimmed = !group.OnPath
+		}
+		if !pa.MergeEdges {
+			groups = append(groups, group)
+			continue
+		}
+
+		i, ok := index[dep.Name]
+		if !ok {
+			index[dep.Name] = len(groups)
+			groups = append(groups, group)
+			continue
+		}
+
+		g := &groups[i]
+		if g.Via == nil {
+			g.Via = dep.Via
+		}
+		g.Mismatch = g.Mismatch || pa.majorMismatch(dep)
+		if containsKind(g.Kinds, dep.Kind) {
+			continue
+		}
+		g.Kinds = append(g.Kinds, dep.Kind)
+		g.Optional = g.Optional && dep.Optional
+		for j := len(g.Kinds) - 1; j > 0 && kindOrder[g.Kinds[j]] < kindOrder[g.Kinds[j-1]]; j-- {
+			g.Kinds[j], g.Kinds[j-1] = g.Kinds[j-1], g.Kinds[j]
+		}
+	}
+
+	return groups
+}
+
+func containsKind(kinds []DependencyKind, kind DependencyKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// mermaidArrow returns the Mermaid link for an edge group, including the
+// merged-kind badge as link text.
+func (g edgeGroup) mermaidArrow() string {
+	arrow := "-->"
+	switch g.Kinds[0] {
+	case KindRequire:
+	case KindBundle:
+		arrow = "==>"
+	default:
+		arrow = "-.->"
+	}
+	if badge := g.badge(); badge != "" {
+		return fmt.Sprintf("%s|%s|", arrow, badge)
+	}
+	return arrow
+}
+
+// dotAttributes returns the Graphviz attribute list for an edge group,
+// colored by theme.
+func (g edgeGroup) dotAttributes(theme *Theme) string {
+	var attrs []string
+	switch g.Kinds[0] {
+	case KindRequireDev:
+		attrs = append(attrs, "style=dashed")
+		if theme.DevEdgeColor != "" {
+			attrs = append(attrs, fmt.Sprintf("color=\"%s\"", theme.DevEdgeColor))
+		}
+	case KindSuggest:
+		attrs = append(attrs, "style=dotted", fmt.Sprintf("color=\"%s\"", theme.SuggestEdgeColor))
+	case KindBundle:
+		attrs = append(attrs, "style=bold")
+	}
+	if badge := g.badge(); badge != "" {
+		attrs = append(attrs, fmt.Sprintf("label=\"%s\"", badge), "fontsize=10")
+	}
+	if g.Users > 1 {
+		attrs = append(attrs, fmt.Sprintf("penwidth=%g", usagePenwidth(g.Users)))
+	}
+	if color, ok := tightnessColors[g.Tightness]; ok {
+		attrs = append(attrs, fmt.Sprintf("color=\"%s\"", color))
+	}
+	if g.Changed {
+		attrs = append(attrs, "color=\"#e67e00\"", "penwidth=2")
+	}
+	if g.OnPath {
+		attrs = append(attrs, "color=\"#1a5fb4\"", "penwidth=3")
+	}
+	if g.Dimmed {
+		attrs = append(attrs, "color=\"#dddddd\"", "fontcolor=\"#bbbbbb\"")
+	}
+	// Graphviz uses the last value of a repeated attribute, so a version
+	// mismatch stays red even on highlighted or dimmed edges.
+	if g.Mismatch {
+		attrs = append(attrs, "color=\"#d00000\"", "fontcolor=\"#d00000\"", "penwidth=2")
+	}
+	if g.Optional {
+		// class is carried into the SVG, so optional edges can be styled.
+		attrs = append(attrs, "class=\"optional\"")
+	}
+	if g.Loose {
+		attrs = append(attrs, "constraint=false")
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
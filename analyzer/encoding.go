@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// utf8BOM is the byte order mark some Windows editors put in front of UTF-8
+// files; encoding/json rejects it.
+var utf8BOM = []byte("\uFEFF")
+
+// ToUTF8 converts the content of a JSON file to UTF-8 without a byte order
+// mark and returns the name of the encoding it was found in, or "" if it
+// already was UTF-8. UTF-16 is
+// recognized by its byte order mark, or without one by the zero bytes next
+// to the ASCII characters JSON starts with. Other invalid UTF-8 is taken to
+// be Latin-1, which every byte sequence is valid in.
+func ToUTF8(data []byte) ([]byte, string, error) {
+	switch {
+	case len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE:
+		return decodeUTF16(data[2:], binary.LittleEndian, "UTF-16LE")
+	case len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF:
+		return decodeUTF16(data[2:], binary.BigEndian, "UTF-16BE")
+	case len(data) >= 2 && data[0] != 0 && data[1] == 0:
+		return decodeUTF16(data, binary.LittleEndian, "UTF-16LE")
+	case len(data) >= 2 && data[0] == 0 && data[1] != 0:
+		return decodeUTF16(data, binary.BigEndian, "UTF-16BE")
+	case utf8.Valid(data):
+		return bytes.TrimPrefix(data, utf8BOM), "", nil
+	}
+
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return []byte(string(runes)), "Latin-1", nil
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder, name string) ([]byte, string, error) {
+	if len(data)%2 != 0 {
+		return nil, name, fmt.Errorf("unexpected encoding: looks like %s but has an odd number of bytes", name)
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	return bytes.TrimPrefix([]byte(string(utf16.Decode(units))), utf8BOM), name, nil
+}
+
+// jsonErrorPosition adds the line, column and byte offset of a syntax or
+// type error to err, counted in the UTF-8 data that was parsed, so the
+// problem can be found in the file. Other errors are returned unchanged.
+func jsonErrorPosition(data []byte, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:])
+	return fmt.Errorf("%w (line %d, column %d, byte offset %d)", err, line, column, offset)
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanBOMPrefixedComposerJSON(t *testing.T) {
+	pa, logs := scanFixture(t, map[string]string{
+		"A": "\uFEFF" + `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b"}`,
+	})
+	if len(pa.ScanErrors) > 0 {
+		t.Fatalf("ScanErrors = %v", pa.ScanErrors)
+	}
+	plugin := pa.Plugins["v/a"]
+	if plugin == nil || len(plugin.Dependencies) != 1 || plugin.Dependencies[0].Name != "v/b" {
+		t.Errorf("BOM-prefixed composer.json not parsed: %+v", plugin)
+	}
+	if strings.Contains(logs.String(), "encoded as") {
+		t.Errorf("UTF-8 with a BOM reported as another encoding:\n%s", logs)
+	}
+}
+
+func TestToUTF8(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		data     []byte
+		want     string
+		encoding string
+	}{
+		{"utf-8", []byte(`{"a": "ü"}`), `{"a": "ü"}`, ""},
+		{"utf-8 bom", []byte("\uFEFF{}"), "{}", ""},
+		{"utf-16le bom", []byte{0xFF, 0xFE, '{', 0, '}', 0}, "{}", "UTF-16LE"},
+		{"utf-16be", []byte{0, '{', 0, '}'}, "{}", "UTF-16BE"},
+		{"latin-1", []byte{'"', 0xFC, '"'}, `"ü"`, "Latin-1"},
+	} {
+		got, encoding, err := ToUTF8(tt.data)
+		if err != nil || string(got) != tt.want || encoding != tt.encoding {
+			t.Errorf("%s: ToUTF8() = %q, %q, %v, want %q, %q", tt.name, got, encoding, err, tt.want, tt.encoding)
+		}
+	}
+}
+
+func TestSyntaxErrorOffset(t *testing.T) {
+	pa, logs := scanFixture(t, map[string]string{
+		"A": "{\n  \"name\": \"v/a\",\n}",
+		"B": `{"name": "v/b"}`,
+	})
+	if len(pa.ScanErrors) != 1 {
+		t.Fatalf("ScanErrors = %v, want the one of A", pa.ScanErrors)
+	}
+	if !strings.Contains(logs.String(), "(line 3, column 1, byte offset") {
+		t.Errorf("parse error lacks its position:\n%s", logs)
+	}
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ExpectedEdge is an edge listed in an expected edges file, with the plugin
+// names as written there.
+type ExpectedEdge struct {
+	From, To string
+	Line     int // line in the file, for error messages
+}
+
+// LoadExpectedEdges reads an expected edges file with one edge per line:
+//
+//	PluginA -> PluginB
+//
+// Plugins are named by folder or composer name. Blank lines and text after
+// "#" are ignored.
+func LoadExpectedEdges(filename string) ([]ExpectedEdge, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected edges: %w", err)
+	}
+	defer file.Close()
+
+	var edges []ExpectedEdge
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(text, "->")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" || strings.Contains(to, "->") {
+			return nil, fmt.Errorf("%s:%d: expected `from -> to`, got %q", filename, line, text)
+		}
+		edges = append(edges, ExpectedEdge{From: from, To: to, Line: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expected edges: %w", err)
+	}
+	return edges, nil
+}
+
+// CompareEdges compares the edges between internal plugins with the
+// expected ones, ignoring order and duplicates. It returns the expected
+// edges the graph lacks and the edges of the graph not expected, both by
+// folder name and sorted. Expected plugins that were not scanned keep their
+// name from the file, so their edges are always missing. Suggest edges
+// don't count, and require-dev edges only with IncludeDev.
+func (pa *PluginAnalyzer) CompareEdges(expected []ExpectedEdge) (missing, unexpected []GraphEdge) {
+	folder := func(name string) string {
+		if plugin := pa.FindPlugin(name); plugin != nil {
+			return plugin.FolderName
+		}
+		return name
+	}
+	want := make(map[GraphEdge]bool)
+	for _, e := range expected {
+		want[GraphEdge{From: folder(e.From), To: folder(e.To)}] = true
+	}
+
+	have := make(map[GraphEdge]bool)
+	for _, name := range pa.InternalPluginNames() {
+		for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+			edge := GraphEdge{From: pa.Plugins[name].FolderName, To: pa.Plugins[dep].FolderName}
+			have[edge] = true
+			if !want[edge] {
+				unexpected = append(unexpected, edge)
+			}
+		}
+	}
+	for edge := range want {
+		if !have[edge] {
+			missing = append(missing, edge)
+		}
+	}
+
+	for _, edges := range [][]GraphEdge{missing, unexpected} {
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i].From != edges[j].From {
+				return edges[i].From < edges[j].From
+			}
+			return edges[i].To < edges[j].To
+		})
+	}
+	return missing, unexpected
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Explain describes one internal plugin, given by composer or folder name:
+// its direct dependencies and dependents, the size of its transitive
+// closure in both directions and every dependency cycle it takes part in.
+func (pa *PluginAnalyzer) Explain(name string) (string, error) {
+	plugin := pa.FindPlugin(name)
+	if plugin == nil {
+		return "", fmt.Errorf("unknown plugin %q", name)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Explanation of %s (%s", plugin.FolderName, plugin.Name))
+	if plugin.Version != "" {
+		sb.WriteString(" " + plugin.Version)
+	}
+	sb.WriteString("):\n")
+
+	var deps []string
+	for _, dep := range plugin.Dependencies {
+		target := pa.Plugins[dep.Name]
+		label := target.FolderName
+		if target.IsExternal {
+			label = dep.Name
+		}
+		entry := fmt.Sprintf("%s (%s", label, dep.Kind)
+		if dep.Constraint != "" {
+			entry += " " + dep.Constraint
+		}
+		deps = append(deps, entry+")")
+	}
+	sort.Strings(deps)
+	writeExplainList(&sb, "Depends on", deps)
+
+	var dependents []string
+	for _, dependent := range pa.directDependents(plugin.Name) {
+		dependents = append(dependents, pa.Plugins[dependent].FolderName)
+	}
+	sort.Strings(dependents)
+	writeExplainList(&sb, "Required by", dependents)
+
+	sb.WriteString(fmt.Sprintf("  Transitive dependencies: %d\n", len(pa.TransitiveDependencies(plugin.Name))))
+	sb.WriteString(fmt.Sprintf("  Transitive dependents: %d\n", len(pa.TransitiveDependents(plugin.Name))))
+
+	var cycles []string
+	for _, cycle := range pa.CyclesContaining(plugin.Name) {
+		cycles = append(cycles, pa.FormatCycle(cycle))
+	}
+	writeExplainList(&sb, "Cycles", cycles)
+
+	return sb.String(), nil
+}
+
+// writeExplainList writes a labeled list, or "none" if it is empty.
+func writeExplainList(sb *strings.Builder, label string, items []string) {
+	if len(items) == 0 {
+		sb.WriteString(fmt.Sprintf("  %s: none\n", label))
+		return
+	}
+	sb.WriteString(fmt.Sprintf("  %s:\n", label))
+	for _, item := range items {
+		sb.WriteString(fmt.Sprintf("    %s\n", item))
+	}
+}
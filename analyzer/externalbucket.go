@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// BucketExternals returns a copy of the analyzer that keeps only the
+// maxExternal external packages with the most users (ExternalDepsCount) as
+// nodes of their own and merges the others into a single node named
+// "other externals (M)". Edges to the merged packages are rerouted to that
+// node, parallel edges of the same kind merged. Missing internal plugins and
+// aggregate nodes of CollapseVendors are never merged. It also returns the
+// merged package names, sorted; without ShowExternalDeps or with no more
+// external packages than maxExternal nothing changes.
+func (pa *PluginAnalyzer) BucketExternals(maxExternal int) (*PluginAnalyzer, []string) {
+	if !pa.ShowExternalDeps {
+		return pa, nil
+	}
+	var externals []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && len(plugin.Collapsed) == 0 && (!pa.isInternalName(name) || pa.libraries[name]) {
+			externals = append(externals, name)
+		}
+	}
+	if len(externals) <= maxExternal {
+		return pa, nil
+	}
+	sort.Slice(externals, func(i, j int) bool {
+		a, b := pa.ExternalDepsCount[externals[i]], pa.ExternalDepsCount[externals[j]]
+		if a != b {
+			return a > b
+		}
+		return externals[i] < externals[j]
+	})
+	merged := externals[maxExternal:]
+	sort.Strings(merged)
+
+	other := &Plugin{IsExternal: true}
+	other.Name = fmt.Sprintf("other externals (%d)", len(merged))
+	other.FolderName = other.Name
+	inBucket := make(map[string]bool)
+	for _, name := range merged {
+		inBucket[name] = true
+	}
+	mapped := func(name string) string {
+		if inBucket[name] {
+			return other.Name
+		}
+		return name
+	}
+
+	bucketed := *pa
+	bucketed.Plugins = map[string]*Plugin{other.Name: other}
+	for name, plugin := range pa.Plugins {
+		if !inBucket[name] {
+			copied := *plugin
+			copied.Dependencies = nil
+			bucketed.Plugins[name] = &copied
+		}
+	}
+	for _, plugin := range pa.sortedPlugins() {
+		from := bucketed.Plugins[mapped(plugin.Name)]
+		for _, dep := range plugin.Dependencies {
+			to := mapped(dep.Name)
+			if to == from.Name || hasDependency(from, to, dep.Kind) {
+				continue
+			}
+			dep.Name = to
+			from.Dependencies = append(from.Dependencies, dep)
+		}
+	}
+	for _, plugin := range bucketed.Plugins {
+		sortDependencies(plugin)
+	}
+
+	bucketed.ExternalDepsCount = make(map[string]int)
+	bucketed.externalUsers = make(map[string]map[string]bool)
+	for dep, users := range pa.externalUsers {
+		for user := range users {
+			if plugin, ok := bucketed.Plugins[mapped(user)]; ok {
+				bucketed.countExternalUse(mapped(dep), plugin)
+			}
+		}
+	}
+	return &bucketed, merged
+}
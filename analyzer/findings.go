@@ -0,0 +1,316 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finding rule identifiers, shared by every report that surfaces problems.
+const (
+	RuleCircularDependency = "circular-dependency"
+	RuleVersionConflict    = "version-conflict"
+	RuleMissingInternal    = "missing-internal-dependency"
+	RuleRedundantDev       = "redundant-dev-requirement"
+	RuleRedundantDirect    = "redundant-direct-dependency"
+	RulePossibleTypo       = "possible-typo"
+	RuleDeniedPackage      = "denied-package"
+	RuleForbiddenEdge      = "forbidden-dependency"
+	RuleVersionMismatch    = "version-mismatch"
+	RuleManifestDeviation  = "manifest-deviation"
+	RuleDeprecatedTarget   = "deprecated-dependency"
+	RuleDuplicateClass     = "duplicate-plugin-class"
+	RuleDuplicateName      = "duplicate-plugin-name"
+)
+
+// Finding is a problem detected in the analyzed plugin set.
+type Finding struct {
+	RuleID  string
+	Level   string // "error", "warning" or "note"
+	Message string
+	Plugin  *Plugin // plugin whose composer.json the finding refers to, if any
+}
+
+// MissingDependency is a required package that matches an internal vendor
+// prefix but was not found among the scanned plugins.
+type MissingDependency struct {
+	Name       string
+	RequiredBy []string
+}
+
+// RedundantDevRequirement is a package a plugin lists in both require and
+// require-dev.
+type RedundantDevRequirement struct {
+	Plugin  string
+	Package string
+}
+
+// RedundantDevRequirements returns every package declared in both the
+// require and require-dev sections of the same plugin, sorted by plugin
+// folder and package name.
+func (pa *PluginAnalyzer) RedundantDevRequirements() []RedundantDevRequirement {
+	var redundant []RedundantDevRequirement
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		for pkg := range plugin.RequireDev {
+			if _, ok := plugin.Require[pkg]; ok {
+				redundant = append(redundant, RedundantDevRequirement{Plugin: plugin.FolderName, Package: pkg})
+			}
+		}
+	}
+
+	sort.Slice(redundant, func(i, j int) bool {
+		if redundant[i].Plugin != redundant[j].Plugin {
+			return redundant[i].Plugin < redundant[j].Plugin
+		}
+		return redundant[i].Package < redundant[j].Package
+	})
+	return redundant
+}
+
+// isInternalName reports whether a package name matches one of the
+// configured internal vendor prefixes, ignoring case. Forced external
+// prefixes win.
+func (pa *PluginAnalyzer) isInternalName(name string) bool {
+	if pa.isForcedExternal(name) {
+		return false
+	}
+	for _, prefix := range pa.InternalPrefixes {
+		if strings.HasPrefix(name, normalizePackageName(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForcedExternal reports whether a package name matches one of the
+// prefixes configured with -external-prefix-force, ignoring case.
+func (pa *PluginAnalyzer) isForcedExternal(name string) bool {
+	for _, prefix := range pa.ForcedExternalPrefixes {
+		if strings.HasPrefix(name, normalizePackageName(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// unscannedInternal reports whether a node stands for a package matching an
+// internal prefix that no scanned plugin provides.
+func (pa *PluginAnalyzer) unscannedInternal(plugin *Plugin) bool {
+	return plugin.IsExternal && pa.isInternalName(plugin.Name) && !pa.libraries[plugin.Name]
+}
+
+// MissingInternalDependencies returns the required packages matching an
+// internal prefix that no scanned plugin provides, sorted by name.
+func (pa *PluginAnalyzer) MissingInternalDependencies() []MissingDependency {
+	missing := make(map[string][]string)
+	for pkg, reqs := range pa.requirements() {
+		if !pa.isInternalName(pkg) {
+			continue
+		}
+		if plugin, ok := pa.Plugins[pkg]; (ok && !plugin.IsExternal) || pa.libraries[pkg] {
+			continue
+		}
+		for _, req := range reqs {
+			missing[pkg] = append(missing[pkg], req.Plugin)
+		}
+	}
+
+	result := make([]MissingDependency, 0, len(missing))
+	for name, requiredBy := range missing {
+		sort.Strings(requiredBy)
+		result = append(result, MissingDependency{Name: name, RequiredBy: requiredBy})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// composerPath returns the path of the composer.json a plugin was read from.
+func (pa *PluginAnalyzer) composerPath(plugin *Plugin) string {
+	return filepath.Join(plugin.Dir, plugin.FolderPath, "composer.json")
+}
+
+// PluginByFolder returns the internal plugin with the given folder name.
+// With IgnoreCase a folder differing only in case matches as well, unless
+// there is an exact match.
+func (pa *PluginAnalyzer) PluginByFolder(folder string) *Plugin {
+	var folded *Plugin
+	for _, plugin := range pa.Plugins {
+		if plugin.IsExternal {
+			continue
+		}
+		if plugin.FolderName == folder {
+			return plugin
+		}
+		if pa.IgnoreCase && strings.EqualFold(plugin.FolderName, folder) {
+			folded = plugin
+		}
+	}
+	return folded
+}
+
+// FindPlugin looks up an internal plugin by folder name or composer name.
+// With IgnoreCase the composer name may be given in any case, like the
+// names in require sections.
+func (pa *PluginAnalyzer) FindPlugin(name string) *Plugin {
+	if pa.IgnoreCase {
+		if plugin, ok := pa.Plugins[normalizePackageName(name)]; ok && !plugin.IsExternal {
+			return plugin
+		}
+	}
+	if plugin, ok := pa.Plugins[name]; ok && !plugin.IsExternal {
+		return plugin
+	}
+	return pa.PluginByFolder(name)
+}
+
+// Findings runs all structural checks and returns their results in a stable
+// order: cycles, version conflicts, missing internal dependencies,
+// redundant dev requirements, redundant direct dependencies, possible typos,
+// denied packages, architecture rule violations, major version mismatches,
+// manifest deviations, dependencies on deprecated plugins, duplicate plugin
+// classes, then duplicate plugin names.
+func (pa *PluginAnalyzer) Findings() []Finding {
+	var findings []Finding
+
+	for _, cycle := range pa.DetectCycles() {
+		findings = append(findings, Finding{
+			RuleID:  RuleCircularDependency,
+			Level:   "error",
+			Message: "Circular dependency: " + pa.FormatCycle(cycle),
+			Plugin:  pa.Plugins[cycle[0]],
+		})
+	}
+
+	for _, conflict := range pa.ConflictingConstraints() {
+		var parts []string
+		for _, req := range conflict.Requirements {
+			parts = append(parts, fmt.Sprintf("%s requires %s", req.Plugin, req.Constraint))
+		}
+		if conflict.RootConflict != "" {
+			parts = append(parts, fmt.Sprintf("root composer.json conflicts with %s", conflict.RootConflict))
+		}
+		findings = append(findings, Finding{
+			RuleID:  RuleVersionConflict,
+			Level:   "error",
+			Message: fmt.Sprintf("Conflicting constraints for %s: %s", conflict.Package, strings.Join(parts, ", ")),
+			Plugin:  pa.PluginByFolder(conflict.Requirements[0].Plugin),
+		})
+	}
+
+	for _, missing := range pa.MissingInternalDependencies() {
+		for _, folder := range missing.RequiredBy {
+			findings = append(findings, Finding{
+				RuleID:  RuleMissingInternal,
+				Level:   "warning",
+				Message: fmt.Sprintf("%s requires %s, which was not found among the scanned plugins", folder, missing.Name),
+				Plugin:  pa.PluginByFolder(folder),
+			})
+		}
+	}
+
+	for _, r := range pa.RedundantDevRequirements() {
+		findings = append(findings, Finding{
+			RuleID:  RuleRedundantDev,
+			Level:   "warning",
+			Message: fmt.Sprintf("%s lists %s in both require and require-dev", r.Plugin, r.Package),
+			Plugin:  pa.PluginByFolder(r.Plugin),
+		})
+	}
+
+	for _, r := range pa.RedundantDependencies() {
+		findings = append(findings, Finding{
+			RuleID:  RuleRedundantDirect,
+			Level:   "note",
+			Message: fmt.Sprintf("%s requires %s, which it already gets through %s", r.Plugin, r.Dependency, r.Via),
+			Plugin:  pa.PluginByFolder(r.Plugin),
+		})
+	}
+
+	for _, t := range pa.PossibleTypos(pa.TypoDistance) {
+		findings = append(findings, Finding{
+			RuleID:  RulePossibleTypo,
+			Level:   "warning",
+			Message: fmt.Sprintf("%s requires %s; did you mean %s?", t.Plugin, t.Required, t.Suggestion),
+			Plugin:  pa.PluginByFolder(t.Plugin),
+		})
+	}
+
+	for _, d := range pa.DeniedRequirements() {
+		findings = append(findings, Finding{
+			RuleID:  RuleDeniedPackage,
+			Level:   "error",
+			Message: fmt.Sprintf("%s requires %s, which is denied by %q", d.Plugin, d.Package, d.Pattern),
+			Plugin:  pa.PluginByFolder(d.Plugin),
+		})
+	}
+
+	for _, v := range pa.RuleViolations() {
+		findings = append(findings, Finding{
+			RuleID:  RuleForbiddenEdge,
+			Level:   "error",
+			Message: fmt.Sprintf("%s depends on %s, which violates %s", v.From, v.To, v.Rule),
+			Plugin:  pa.PluginByFolder(v.From),
+		})
+	}
+
+	for _, m := range pa.VersionMismatches() {
+		findings = append(findings, Finding{
+			RuleID:  RuleVersionMismatch,
+			Level:   "error",
+			Message: fmt.Sprintf("%s requires %s %s, but %s is at %s", m.Plugin, m.Target, m.Constraint, m.Target, m.Version),
+			Plugin:  pa.PluginByFolder(m.Plugin),
+		})
+	}
+
+	if pa.Manifest != nil {
+		for _, d := range pa.VerifyManifest(pa.Manifest) {
+			findings = append(findings, Finding{
+				RuleID:  RuleManifestDeviation,
+				Level:   "error",
+				Message: fmt.Sprintf("%s: %s → %s", d.Kind, d.From, d.To),
+				Plugin:  pa.PluginByFolder(d.From),
+			})
+		}
+	}
+
+	for _, d := range pa.DeprecatedDependencies() {
+		findings = append(findings, Finding{
+			RuleID:  RuleDeprecatedTarget,
+			Level:   "warning",
+			Message: fmt.Sprintf("%s depends on deprecated plugin %s", d.Plugin, d.Target),
+			Plugin:  pa.PluginByFolder(d.Plugin),
+		})
+	}
+
+	for _, d := range pa.DuplicatePluginClasses() {
+		findings = append(findings, Finding{
+			RuleID:  RuleDuplicateClass,
+			Level:   "error",
+			Message: fmt.Sprintf("%s is declared as shopware-plugin-class by %s", d.Class, strings.Join(d.Plugins, ", ")),
+			Plugin:  pa.PluginByFolder(d.Plugins[0]),
+		})
+	}
+
+	for _, d := range pa.DuplicatePluginNames() {
+		findings = append(findings, Finding{
+			RuleID:  RuleDuplicateName,
+			Level:   "error",
+			Message: fmt.Sprintf("%s is declared by %s and %s; only %s is analyzed", d.Name, d.Kept, strings.Join(d.Ignored, ", "), d.Kept),
+			Plugin:  pa.Plugins[d.Name],
+		})
+	}
+
+	return findings
+}
+
+// FormatCycle renders a cycle as "A → B → C → A" using folder names.
+func (pa *PluginAnalyzer) FormatCycle(cycle []string) string {
+	names := make([]string, 0, len(cycle)+1)
+	for _, name := range cycle {
+		names = append(names, pa.Plugins[name].FolderName)
+	}
+	names = append(names, pa.Plugins[cycle[0]].FolderName)
+	return strings.Join(names, " → ")
+}
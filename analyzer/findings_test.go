@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedundantDevRequirements(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*", "x/lib": "^1.0"}, "require-dev": {"x/lib": "^1.0", "phpunit/phpunit": "^9"}}`,
+		"B": `{"name": "v/b", "require-dev": {"v/a": "*"}}`,
+	})
+	want := []RedundantDevRequirement{{Plugin: "A", Package: "x/lib"}}
+	if got := pa.RedundantDevRequirements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedundantDevRequirements() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindPluginIgnoreCase(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"PluginA": `{"name": "Vendor/Plugin-A"}`,
+		"pluginb": `{"name": "vendor/plugin-b"}`,
+		"PluginB": `{"name": "vendor/plugin-b2"}`,
+	})
+	for _, tt := range []struct {
+		name       string
+		ignoreCase bool
+		want       string // folder, "" for no match
+	}{
+		{"PluginA", false, "PluginA"},
+		{"vendor/plugin-a", false, "PluginA"},
+		{"pluginA", false, ""},
+		{"pluginA", true, "PluginA"},
+		{"VENDOR/Plugin-A", true, "PluginA"},
+		{"PluginB", true, "PluginB"}, // an exact match wins
+		{"pluginb", true, "pluginb"},
+	} {
+		pa.IgnoreCase = tt.ignoreCase
+		got := ""
+		if plugin := pa.FindPlugin(tt.name); plugin != nil {
+			got = plugin.FolderName
+		}
+		if got != tt.want {
+			t.Errorf("FindPlugin(%q) with IgnoreCase=%v = %q, want %q", tt.name, tt.ignoreCase, got, tt.want)
+		}
+	}
+}
+
+func TestExcludeIgnoreCase(t *testing.T) {
+	composers := map[string]string{
+		"TestPlugin": `{"name": "v/test-plugin"}`,
+		"Shop":       `{"name": "v/shop", "require": {"v/test-plugin": "*"}}`,
+	}
+	for _, ignoreCase := range []bool{false, true} {
+		pa, _ := scanFixture(t, composers, func(pa *PluginAnalyzer) {
+			pa.IgnoreCase = ignoreCase
+			pa.ExcludePatterns = []string{"test*"}
+		})
+		_, scanned := pa.Plugins["v/test-plugin"]
+		if scanned == ignoreCase {
+			t.Errorf("IgnoreCase=%v: TestPlugin scanned = %v", ignoreCase, scanned)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// githubCommands maps a Finding level to its workflow command.
+var githubCommands = map[string]string{
+	"error":   "error",
+	"warning": "warning",
+	"note":    "notice",
+}
+
+// GitHubAnnotations returns the skipped plugin folders and the analyzer's
+// findings as GitHub Actions workflow commands, one per line, e.g.
+// "::error file=plugins/A/composer.json,title=circular-dependency::...".
+// Annotations are anchored at the composer.json of the plugin concerned,
+// so the Actions UI shows them next to the file.
+func (pa *PluginAnalyzer) GitHubAnnotations() string {
+	var sb strings.Builder
+	for _, e := range pa.ScanErrors {
+		file := filepath.Join(e.Dir, e.Folder, "composer.json")
+		writeGitHubCommand(&sb, "warning", file, "scan-error", e.Error())
+	}
+	for _, f := range pa.Findings() {
+		file := ""
+		if f.Plugin != nil {
+			file = pa.composerPath(f.Plugin)
+		}
+		writeGitHubCommand(&sb, githubCommands[f.Level], file, f.RuleID, f.Message)
+	}
+	return sb.String()
+}
+
+// writeGitHubCommand writes one workflow command line, escaping the
+// properties and message as the runner expects.
+func writeGitHubCommand(sb *strings.Builder, command, file, title, message string) {
+	var props []string
+	if file != "" {
+		props = append(props, "file="+escapeGitHubProperty(filepath.ToSlash(file)))
+	}
+	props = append(props, "title="+escapeGitHubProperty(title))
+	fmt.Fprintf(sb, "::%s %s::%s\n", command, strings.Join(props, ","), escapeGitHubData(message))
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// GraphEdge is an edge of a GraphDiff, by folder name.
+type GraphEdge struct {
+	From, To string
+	Kind     DependencyKind
+}
+
+// GraphDiff lists how a dependency graph changed between two scans. Nodes
+// and edges are matched by composer name and reported by folder name, each
+// list in the order of GenerateJSON.
+type GraphDiff struct {
+	AddedPlugins   []string
+	RemovedPlugins []string
+	AddedEdges     []GraphEdge
+	RemovedEdges   []GraphEdge
+}
+
+// Empty reports whether the graphs were the same.
+func (d GraphDiff) Empty() bool {
+	return len(d.AddedPlugins)+len(d.RemovedPlugins)+len(d.AddedEdges)+len(d.RemovedEdges) == 0
+}
+
+// LoadJSONGraph reads a graph written by the json format into an analyzer,
+// so it can be compared with Diff. External nodes are kept as they are in
+// the file.
+func LoadJSONGraph(path string) (*PluginAnalyzer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON graph: %w", err)
+	}
+	var graph jsonGraph
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON graph %s: %w", path, err)
+	}
+
+	pa := NewPluginAnalyzer(nil, true)
+	for _, node := range graph.Nodes {
+		pa.Plugins[node.Name] = &Plugin{Name: node.Name, FolderName: node.FolderName, IsExternal: node.IsExternal}
+	}
+	for _, edge := range graph.Edges {
+		plugin, ok := pa.Plugins[edge.From]
+		if !ok {
+			return nil, fmt.Errorf("JSON graph %s: edge from unknown node %q", path, edge.From)
+		}
+		if _, ok := pa.Plugins[edge.To]; !ok {
+			return nil, fmt.Errorf("JSON graph %s: edge to unknown node %q", path, edge.To)
+		}
+		plugin.Dependencies = append(plugin.Dependencies, Dependency{Name: edge.To, Kind: edge.Kind})
+	}
+	return pa, nil
+}
+
+// Diff returns the plugins and edges of the graph of current that are not
+// in the one of old, and the other way round. Both graphs are taken as
+// GenerateJSON would write them, so external packages only count for an
+// analyzer with ShowExternalDeps.
+func Diff(old, current *PluginAnalyzer) GraphDiff {
+	before, after := old.graphDocument(), current.graphDocument()
+	var diff GraphDiff
+
+	folders := make(map[string]string)
+	beforeNodes := make(map[string]bool)
+	for _, node := range before.Nodes {
+		beforeNodes[node.Name] = true
+		folders[node.Name] = node.FolderName
+	}
+	afterNodes := make(map[string]bool)
+	for _, node := range after.Nodes {
+		afterNodes[node.Name] = true
+		folders[node.Name] = node.FolderName
+		if !beforeNodes[node.Name] {
+			diff.AddedPlugins = append(diff.AddedPlugins, node.FolderName)
+		}
+	}
+	for _, node := range before.Nodes {
+		if !afterNodes[node.Name] {
+			diff.RemovedPlugins = append(diff.RemovedPlugins, node.FolderName)
+		}
+	}
+
+	beforeEdges := make(map[jsonEdge]bool)
+	for _, edge := range before.Edges {
+		beforeEdges[edge] = true
+	}
+	afterEdges := make(map[jsonEdge]bool)
+	for _, edge := range after.Edges {
+		afterEdges[edge] = true
+		if !beforeEdges[edge] {
+			diff.AddedEdges = append(diff.AddedEdges, GraphEdge{From: folders[edge.From], To: folders[edge.To], Kind: edge.Kind})
+		}
+	}
+	for _, edge := range before.Edges {
+		if !afterEdges[edge] {
+			diff.RemovedEdges = append(diff.RemovedEdges, GraphEdge{From: folders[edge.From], To: folders[edge.To], Kind: edge.Kind})
+		}
+	}
+	return diff
+}
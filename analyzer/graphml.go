@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+const graphmlNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphmlDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphmlKey `xml:"key"`
+	Graph   graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+}
+
+type graphmlNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphmlData `xml:"data"`
+}
+
+type graphmlEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphmlData `xml:"data"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphmlKeys declares the node and edge attributes; the names are the ones
+// yEd and Gephi pick up as label and properties.
+var graphmlKeys = []graphmlKey{
+	{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+	{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+	{ID: "external", For: "node", AttrName: "external", AttrType: "boolean"},
+	{ID: "version", For: "node", AttrName: "version", AttrType: "string"},
+	{ID: "kind", For: "edge", AttrName: "kind", AttrType: "string"},
+	{ID: "constraint", For: "edge", AttrName: "constraint", AttrType: "string"},
+}
+
+// GenerateGraphML returns the graph as a GraphML document for yEd, Gephi and
+// other graph tools. Composer names may hold characters not allowed in XML
+// IDs, so nodes get the IDs n0, n1, ... in the order of their composer names
+// and carry the folder name as label and the composer name, external flag
+// and version as data. Edges are directed and carry their dependency kind.
+func (pa *PluginAnalyzer) GenerateGraphML() (string, error) {
+	doc := graphmlDocument{Xmlns: graphmlNamespace, Keys: graphmlKeys}
+	doc.Graph = graphmlGraph{ID: "dependencies", EdgeDefault: "directed"}
+
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ids := make(map[string]string, len(names))
+	for i, name := range names {
+		plugin := pa.Plugins[name]
+		ids[name] = fmt.Sprintf("n%d", i)
+		node := graphmlNode{ID: ids[name], Data: []graphmlData{
+			{Key: "label", Value: plugin.FolderName},
+			{Key: "name", Value: plugin.Name},
+			{Key: "external", Value: fmt.Sprint(plugin.IsExternal)},
+		}}
+		if plugin.Version != "" {
+			node.Data = append(node.Data, graphmlData{Key: "version", Value: plugin.Version})
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for _, name := range names {
+		for _, dep := range pa.Plugins[name].Dependencies {
+			target, ok := ids[dep.Name]
+			if !ok {
+				continue
+			}
+			edge := graphmlEdge{
+				ID:     fmt.Sprintf("e%d", len(doc.Graph.Edges)),
+				Source: ids[name],
+				Target: target,
+				Data:   []graphmlData{{Key: "kind", Value: string(dep.Kind)}},
+			}
+			if dep.Constraint != "" {
+				edge.Data = append(edge.Data, graphmlData{Key: "constraint", Value: dep.Constraint})
+			}
+			doc.Graph.Edges = append(doc.Graph.Edges, edge)
+		}
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(data) + "\n", nil
+}
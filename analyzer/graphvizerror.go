@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GraphvizError is returned when a Graphviz layout engine fails. It keeps
+// what the engine printed to stderr, which names the cause, e.g. a syntax
+// error in the generated DOT or a missing font.
+type GraphvizError struct {
+	Engine   string
+	ExitCode int    // -1 if the engine didn't start or was killed
+	Stderr   string // trimmed
+	Err      error
+}
+
+func (e *GraphvizError) Error() string {
+	if e.ExitCode < 0 {
+		return fmt.Sprintf("failed to run %s command: %v", e.Engine, e.Err)
+	}
+	msg := fmt.Sprintf("%s exited with status %d", e.Engine, e.ExitCode)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *GraphvizError) Unwrap() error {
+	return e.Err
+}
+
+// runGraphviz runs a Graphviz command, returning a *GraphvizError with its
+// stderr if it fails.
+func runGraphviz(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	gvErr := &GraphvizError{Engine: cmd.Args[0], ExitCode: -1, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		gvErr.ExitCode = exitErr.ExitCode()
+	}
+	return gvErr
+}
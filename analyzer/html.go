@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+const defaultReportCSS = `body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+tr.external td { background: #ffe0e0; }
+tr.highlighted td { font-weight: bold; }
+td details ul { margin: 0.25em 0; padding-left: 1.25em; }
+.mermaid, .graph { margin-bottom: 2em; }
+.graph svg { max-width: 100%; height: auto; }`
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Plugin Dependencies</title>
+<style>
+{{.DefaultCSS}}
+</style>
+{{- if .CustomCSS}}
+<style>
+{{.CustomCSS}}
+</style>
+{{- end}}
+{{- if not .SVG}}
+<script src="https://cdn.jsdelivr.net/npm/mermaid/dist/mermaid.min.js"></script>
+<script>mermaid.initialize({ startOnLoad: true });</script>
+{{- end}}
+</head>
+<body>
+<h1>Plugin Dependencies</h1>
+{{- if .SVG}}
+<div class="graph">
+{{.SVG}}
+</div>
+{{- else}}
+<div class="mermaid">
+{{.Mermaid}}
+</div>
+{{- end}}
+<h2>Statistics</h2>
+<table class="statistics">
+{{- range .Stats}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+<h2>Plugins</h2>
+<table class="plugins">
+<tr><th>Plugin</th><th>Composer name</th><th>Dependencies</th></tr>
+{{- range .Plugins}}
+<tr class="{{.Class}}"><td>{{.FolderName}}</td><td>{{.Name}}</td><td>{{template "list" .DependencyList}}</td></tr>
+{{- end}}
+</table>
+{{- if .External}}
+<h2>External Dependencies</h2>
+<table class="external-dependencies">
+<tr><th>Package</th><th>Used by</th></tr>
+{{- range .External}}
+<tr class="external"><td>{{.Name}}</td><td>{{template "list" .Users}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+{{- define "list"}}{{if .}}<details><summary>{{len .}}</summary><ul>{{range .}}<li>{{.}}</li>{{end}}</ul></details>{{else}}0{{end}}{{end}}
+`))
+
+type htmlPluginRow struct {
+	Name           string
+	FolderName     string
+	DependencyList []string // folder names, external packages marked as such
+	Class          string
+}
+
+type htmlExternalRow struct {
+	Name  string
+	Users []string // folder names, sorted
+}
+
+type htmlStatRow struct {
+	Label, Value string
+}
+
+// htmlStats returns the rows of the statistics table, labeled like the
+// "Graph Statistics" section.
+func htmlStats(stats GraphStats) []htmlStatRow {
+	mostDependedUpon := "none"
+	if stats.MostDependedUpon != "" {
+		mostDependedUpon = fmt.Sprintf("%s (%d dependents)", stats.MostDependedUpon, stats.MostDependedUponFanIn)
+	}
+	return []htmlStatRow{
+		{"Internal plugins", fmt.Sprint(stats.InternalPlugins)},
+		{"External dependencies", fmt.Sprint(stats.ExternalDependencies)},
+		{"Edges", fmt.Sprint(stats.Edges)},
+		{"Cycles", fmt.Sprint(stats.Cycles)},
+		{"Max depth", fmt.Sprint(stats.MaxDepth)},
+		{"Most depended upon", mostDependedUpon},
+	}
+}
+
+// inlineSVG renders the Graphviz graph as SVG markup to embed in a page,
+// without the XML declaration and doctype that precede the <svg> element.
+func (pa *PluginAnalyzer) inlineSVG() (template.HTML, error) {
+	svg, err := pa.RenderSVG()
+	if err != nil {
+		return "", err
+	}
+	start := bytes.Index(svg, []byte("<svg"))
+	if start < 0 {
+		return "", fmt.Errorf("no <svg> element in the Graphviz output")
+	}
+	return template.HTML(svg[start:]), nil
+}
+
+// nodeClass returns the semantic CSS classes of a plugin's node, in the SVG
+// and Mermaid graphs alike, and of its table row: "internal" or "external",
+// plus "highlighted" for Changed plugins and those on HighlightPath.
+func (pa *PluginAnalyzer) nodeClass(plugin *Plugin) string {
+	class := "internal"
+	if plugin.IsExternal {
+		class = "external"
+	}
+	if pa.Changed[plugin.Name] || pa.onHighlightedPath(plugin.Name) {
+		class += " highlighted"
+	}
+	return class
+}
+
+// mermaidWithClasses returns the Mermaid graph with every node assigned its
+// semantic classes so that stylesheets can target ".internal", ".external"
+// and ".highlighted".
+func (pa *PluginAnalyzer) mermaidWithClasses() string {
+	var sb strings.Builder
+	sb.WriteString(pa.GenerateMermaid())
+	sb.WriteString("    classDef internal fill:#f0f0f0\n")
+	sb.WriteString("    classDef external fill:#ffe0e0\n")
+	sb.WriteString("    classDef highlighted stroke:#e67e00,stroke-width:3px\n")
+
+	var names []string
+	for name := range pa.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		// A Mermaid class statement assigns a single class.
+		for _, class := range strings.Fields(pa.nodeClass(plugin)) {
+			sb.WriteString(fmt.Sprintf("    class \"%s\" %s;\n", plugin.FolderName, class))
+		}
+	}
+	return sb.String()
+}
+
+// GenerateHTML renders a self-contained HTML report with the graph, the
+// graph statistics and plugin tables whose dependency lists fold out. The
+// graph is embedded as inline SVG, so the report works offline; without
+// Graphviz it falls back to a Mermaid graph rendered by a script from a CDN.
+// Nodes and rows carry the classes "internal" or "external" and
+// "highlighted"; customCSS, if not empty, is injected after the default
+// styles.
+func (pa *PluginAnalyzer) GenerateHTML(customCSS string) (string, error) {
+	var plugins []htmlPluginRow
+	for _, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		var deps []string
+		for _, dep := range plugin.Dependencies {
+			target := pa.Plugins[dep.Name]
+			if target.IsExternal {
+				deps = append(deps, dep.Name+" (external)")
+			} else {
+				deps = append(deps, target.FolderName)
+			}
+		}
+		sort.Strings(deps)
+		plugins = append(plugins, htmlPluginRow{
+			Name:           plugin.Name,
+			FolderName:     plugin.FolderName,
+			DependencyList: deps,
+			Class:          pa.nodeClass(plugin),
+		})
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].FolderName < plugins[j].FolderName })
+
+	var external []htmlExternalRow
+	for name := range pa.ExternalDepsCount {
+		var users []string
+		for user := range pa.externalUsers[name] {
+			if plugin, ok := pa.Plugins[user]; ok {
+				user = plugin.FolderName
+			} // else hidden from the rendered graph, shown by composer name
+			users = append(users, user)
+		}
+		sort.Strings(users)
+		external = append(external, htmlExternalRow{Name: name, Users: users})
+	}
+	sort.Slice(external, func(i, j int) bool { return external[i].Name < external[j].Name })
+
+	svg, err := pa.inlineSVG()
+	if err != nil {
+		svg = "" // Graphviz is optional here, Mermaid draws the graph instead
+	}
+
+	var sb strings.Builder
+	err = htmlReportTemplate.Execute(&sb, map[string]interface{}{
+		"DefaultCSS": template.CSS(defaultReportCSS),
+		"CustomCSS":  template.CSS(customCSS),
+		"SVG":        svg,
+		"Mermaid":    pa.mermaidWithClasses(),
+		"Stats":      htmlStats(pa.Stats()),
+		"Plugins":    plugins,
+		"External":   external,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return sb.String(), nil
+}
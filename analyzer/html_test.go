@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNodeClassesInDOTAndMermaid(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*", "symfony/console": "*"}}`,
+		"B": `{"name": "v/b"}`,
+	}, showExternal)
+
+	var dot strings.Builder
+	if err := pa.GenerateDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	mermaid := pa.mermaidWithClasses()
+	for _, node := range []struct{ name, folder, class string }{
+		{"v/a", "A", "internal"},
+		{"v/b", "B", "internal"},
+		{"symfony/console", "symfony/console", "external"},
+	} {
+		var line string
+		for _, l := range strings.Split(dot.String(), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(l), fmt.Sprintf("%q [", node.name)) {
+				line = l
+			}
+		}
+		if !strings.Contains(line, fmt.Sprintf("class=%q", node.class)) {
+			t.Errorf("DOT node of %s lacks class=%q: %q", node.name, node.class, line)
+		}
+		if want := fmt.Sprintf("class %q %s;", node.folder, node.class); !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid graph lacks %s:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestHTMLHighlightedClass(t *testing.T) {
+	const customCSS = ".highlighted { stroke: red; }"
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b"}`,
+	})
+	pa.Changed = map[string]bool{"v/a": true}
+
+	t.Run("svg", func(t *testing.T) {
+		fakeGraphviz(t)
+		report, err := pa.GenerateHTML(customCSS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The fake engine echoes the escaped DOT into the SVG.
+		for _, want := range []string{customCSS, `<tr class="internal highlighted"><td>A</td>`, `<tr class="internal"><td>B</td>`, `class=&#34;internal highlighted&#34;`} {
+			if !strings.Contains(report, want) {
+				t.Errorf("report lacks %s:\n%s", want, report)
+			}
+		}
+	})
+
+	t.Run("mermaid", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "dot")
+		graphvizCommand = func(string, ...string) *exec.Cmd { return exec.Command(missing) }
+		t.Cleanup(func() { graphvizCommand = exec.Command })
+		report, err := pa.GenerateHTML(customCSS)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// html/template escapes the quotes in the Mermaid source.
+		for _, want := range []string{customCSS, "classDef highlighted ", `class &#34;A&#34; highlighted;`} {
+			if !strings.Contains(report, want) {
+				t.Errorf("report lacks %s:\n%s", want, report)
+			}
+		}
+		if strings.Contains(report, `class &#34;B&#34; highlighted;`) {
+			t.Errorf("unchanged B is highlighted:\n%s", report)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import "fmt"
+
+// HideHubs returns a copy of the analyzer without the plugins that depend on
+// more than maxFanOut distinct nodes of the rendered graph. With stubs set,
+// such a hub stays as a node labeled with its hidden dependency count and
+// keeps its incoming edges, but loses its outgoing ones.
+func (pa *PluginAnalyzer) HideHubs(maxFanOut int, stubs bool) *PluginAnalyzer {
+	hubs := make(map[string]int)
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal {
+			continue
+		}
+		targets := make(map[string]bool)
+		for _, dep := range plugin.Dependencies {
+			if target, ok := pa.Plugins[dep.Name]; ok && (!target.IsExternal || pa.ShowExternalDeps) {
+				targets[dep.Name] = true
+			}
+		}
+		if len(targets) > maxFanOut {
+			hubs[name] = len(targets)
+		}
+	}
+
+	// External packages only the hidden hubs required go as well.
+	keep := make(map[string]bool)
+	for name, plugin := range pa.Plugins {
+		if _, hub := hubs[name]; plugin.IsExternal || (hub && !stubs) {
+			continue
+		}
+		keep[name] = true
+		if _, hub := hubs[name]; hub {
+			continue
+		}
+		for _, dep := range plugin.Dependencies {
+			if _, hub := hubs[dep.Name]; !hub {
+				keep[dep.Name] = true
+			}
+		}
+	}
+	sub := pa.subset(keep)
+	// Hubs still use their packages, they just aren't drawn.
+	sub.ExternalDepsCount, sub.externalUsers = pa.ExternalDepsCount, pa.externalUsers
+	if stubs {
+		for name, fanOut := range hubs {
+			stub := sub.Plugins[name]
+			if stub.Label != "" {
+				stub.Label = fmt.Sprintf("%s (hub, %d dependencies hidden)", stub.Label, fanOut)
+			}
+			stub.FolderName = fmt.Sprintf("%s (hub, %d dependencies hidden)", stub.FolderName, fanOut)
+			stub.Dependencies = nil
+		}
+	}
+	return sub
+}
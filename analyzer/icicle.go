@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+const (
+	icicleWidth     = 1200.0
+	icicleRowHeight = 28.0
+)
+
+// icicleColors are the fill colors of the icicle rows, by depth.
+var icicleColors = []string{"#c6dbef", "#9ecae1", "#6baed6", "#4292c6", "#2171b5", "#08519c"}
+
+// icicleNode is one box of the icicle layout.
+type icicleNode struct {
+	Name     string
+	Size     int // 1 + number of transitive dependencies
+	Children []*icicleNode
+}
+
+// buildIcicle expands the dependency tree below name. Plugins already on the
+// current path are not expanded again, so cycles terminate.
+func (pa *PluginAnalyzer) buildIcicle(name string, path map[string]bool, sizes map[string]int) *icicleNode {
+	size, ok := sizes[name]
+	if !ok {
+		size = 1 + len(pa.TransitiveDependencies(name))
+		sizes[name] = size
+	}
+	node := &icicleNode{Name: name, Size: size}
+
+	path[name] = true
+	for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+		if !path[dep] {
+			node.Children = append(node.Children, pa.buildIcicle(dep, path, sizes))
+		}
+	}
+	delete(path, name)
+
+	return node
+}
+
+// icicleRoots returns the composer names of the plugins the icicle starts
+// from: the given ones, or every plugin without internal dependents.
+func (pa *PluginAnalyzer) icicleRoots(roots []string) ([]string, error) {
+	if len(roots) > 0 {
+		var names []string
+		for _, root := range roots {
+			plugin := pa.FindPlugin(root)
+			if plugin == nil {
+				return nil, fmt.Errorf("plugin %q not found", root)
+			}
+			names = append(names, plugin.Name)
+		}
+		return names, nil
+	}
+
+	var names []string
+	metrics := pa.Metrics()
+	for _, name := range pa.InternalPluginNames() {
+		if metrics[name].FanIn == 0 {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// GenerateIcicle renders an icicle diagram as SVG. Each row is one level of
+// the dependency tree below the roots, and the width of a box is
+// proportional to 1 + the plugin's transitive dependency count, so plugins
+// dragging in large subtrees stand out.
+func (pa *PluginAnalyzer) GenerateIcicle(roots []string) (string, error) {
+	rootNames, err := pa.icicleRoots(roots)
+	if err != nil {
+		return "", err
+	}
+
+	sizes := make(map[string]int)
+	var trees []*icicleNode
+	for _, name := range rootNames {
+		trees = append(trees, pa.buildIcicle(name, make(map[string]bool), sizes))
+	}
+
+	var body strings.Builder
+	maxDepth := 0
+	var draw func(nodes []*icicleNode, x, width float64, depth int)
+	draw = func(nodes []*icicleNode, x, width float64, depth int) {
+		total := 0
+		for _, n := range nodes {
+			total += n.Size
+		}
+		if total == 0 {
+			return
+		}
+		if depth > maxDepth {
+			maxDepth = depth
+		}
+		for _, n := range nodes {
+			w := width * float64(n.Size) / float64(total)
+			y := float64(depth) * icicleRowHeight
+			label := fmt.Sprintf("%s (%d)", pa.Plugins[n.Name].FolderName, n.Size-1)
+			body.WriteString(fmt.Sprintf("  <g><title>%s</title>", html.EscapeString(label)))
+			body.WriteString(fmt.Sprintf(`<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s" stroke="#ffffff"/>`,
+				x, y, w, icicleRowHeight, icicleColors[depth%len(icicleColors)]))
+			if w > 40 {
+				body.WriteString(fmt.Sprintf(`<text x="%.2f" y="%.2f" font-family="sans-serif" font-size="12">%s</text>`,
+					x+4, y+icicleRowHeight/2+4, html.EscapeString(label)))
+			}
+			body.WriteString("</g>\n")
+			draw(n.Children, x, w, depth+1)
+			x += w
+		}
+	}
+	draw(trees, 0, icicleWidth, 0)
+
+	height := float64(maxDepth+1) * icicleRowHeight
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n",
+		icicleWidth, height, icicleWidth, height))
+	sb.WriteString(body.String())
+	sb.WriteString("</svg>\n")
+	return sb.String(), nil
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// interactiveNode is the per-node data embedded into the interactive page.
+type interactiveNode struct {
+	ID           string   `json:"id"`
+	Label        string   `json:"label"`
+	External     bool     `json:"external"`
+	Dependencies []string `json:"dependencies"`
+	Dependents   []string `json:"dependents"`
+}
+
+var interactiveTemplate = template.Must(template.New("interactive").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Plugin Dependencies</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+ul { list-style: none; padding-left: 1.5em; margin: 0; }
+li > span { cursor: pointer; padding: 1px 4px; border-radius: 3px; }
+li > span:hover { background: #e8f0fe; }
+.toggle { display: inline-block; width: 1em; color: #666; }
+.external > span { background: #ffe0e0; }
+.cycle { color: #c00; font-style: italic; }
+#info { position: fixed; top: 2em; right: 2em; width: 25em; padding: 1em; border: 1px solid #ccc; background: #fafafa; }
+</style>
+</head>
+<body>
+<h1>Plugin Dependencies</h1>
+<p>Click a plugin to expand or collapse its dependencies. Hover to see who depends on it.</p>
+<div id="info">Hover a plugin to see its dependents.</div>
+<ul id="tree"></ul>
+<script>
+var nodes = {{.Nodes}};
+var roots = {{.Roots}};
+var byId = {};
+nodes.forEach(function (n) { byId[n.id] = n; });
+
+function showInfo(node) {
+  var info = document.getElementById("info");
+  var names = node.dependents.map(function (id) { return byId[id].label; });
+  info.textContent = node.label + " is used by: " + (names.length ? names.join(", ") : "no plugin");
+}
+
+function render(id, path) {
+  var node = byId[id];
+  var li = document.createElement("li");
+  if (node.external) { li.className = "external"; }
+  var label = document.createElement("span");
+  var toggle = document.createElement("span");
+  toggle.className = "toggle";
+  label.appendChild(toggle);
+  label.appendChild(document.createTextNode(node.label));
+  label.addEventListener("mouseover", function () { showInfo(node); });
+  li.appendChild(label);
+
+  if (path.indexOf(id) >= 0) {
+    var cycle = document.createElement("span");
+    cycle.className = "cycle";
+    cycle.textContent = " (cycle)";
+    li.appendChild(cycle);
+    return li;
+  }
+  if (!node.dependencies.length) { return li; }
+
+  toggle.textContent = "+";
+  var children = null;
+  label.addEventListener("click", function () {
+    if (children === null) {
+      children = document.createElement("ul");
+      node.dependencies.forEach(function (dep) { children.appendChild(render(dep, path.concat([id]))); });
+      li.appendChild(children);
+    } else {
+      children.hidden = !children.hidden;
+    }
+    toggle.textContent = children.hidden ? "+" : "-";
+  });
+  return li;
+}
+
+var tree = document.getElementById("tree");
+roots.forEach(function (id) { tree.appendChild(render(id, [])); });
+</script>
+</body>
+</html>
+`))
+
+// GenerateInteractiveHTML renders a standalone HTML page showing the plugins
+// as a tree that can be expanded and collapsed per node. The graph is
+// embedded as JSON, so the page works offline.
+func (pa *PluginAnalyzer) GenerateInteractiveHTML() (string, error) {
+	var nodes []interactiveNode
+	var roots []string
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dependents := make(map[string][]string)
+	for _, name := range names {
+		for _, dep := range pa.uniqueTargets(pa.Plugins[name]) {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		node := interactiveNode{
+			ID:           name,
+			Label:        plugin.FolderName,
+			External:     plugin.IsExternal,
+			Dependencies: pa.uniqueTargets(plugin),
+			Dependents:   dependents[name],
+		}
+		if node.Dependencies == nil {
+			node.Dependencies = []string{}
+		}
+		if node.Dependents == nil {
+			node.Dependents = []string{}
+			if !plugin.IsExternal {
+				roots = append(roots, name)
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	// Plugins only reachable through a cycle have dependents but are not
+	// below any root; list the first unreached one of each such group too.
+	reached := make(map[string]bool)
+	var mark func(name string)
+	mark = func(name string) {
+		if reached[name] {
+			return
+		}
+		reached[name] = true
+		for _, dep := range pa.uniqueTargets(pa.Plugins[name]) {
+			mark(dep)
+		}
+	}
+	for _, root := range roots {
+		mark(root)
+	}
+	for _, name := range pa.InternalPluginNames() {
+		if !reached[name] {
+			roots = append(roots, name)
+			mark(name)
+		}
+	}
+
+	var sb strings.Builder
+	if err := interactiveTemplate.Execute(&sb, map[string]interface{}{"Nodes": nodes, "Roots": roots}); err != nil {
+		return "", fmt.Errorf("failed to render interactive HTML: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// uniqueTargets returns the sorted, de-duplicated names of the nodes plugin
+// has a visible edge to.
+func (pa *PluginAnalyzer) uniqueTargets(plugin *Plugin) []string {
+	seen := make(map[string]bool)
+	var targets []string
+	for _, dep := range plugin.Dependencies {
+		target, ok := pa.Plugins[dep.Name]
+		if !ok || seen[dep.Name] || (target.IsExternal && !pa.ShowExternalDeps) {
+			continue
+		}
+		seen[dep.Name] = true
+		targets = append(targets, dep.Name)
+	}
+	sort.Strings(targets)
+	return targets
+}
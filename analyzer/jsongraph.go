@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// jsonGraph is the document written by the json format.
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	Name       string `json:"name"`
+	FolderName string `json:"folderName"`
+	IsExternal bool   `json:"isExternal"`
+}
+
+type jsonEdge struct {
+	From string         `json:"from"`
+	To   string         `json:"to"`
+	Kind DependencyKind `json:"kind"`
+}
+
+// GenerateJSON returns the graph as JSON: the nodes sorted by composer name
+// and the directed edges between them sorted by source, target and kind, so
+// the file only changes when the graph does.
+func (pa *PluginAnalyzer) GenerateJSON() ([]byte, error) {
+	data, err := json.MarshalIndent(pa.graphDocument(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// graphDocument returns the nodes and edges written by GenerateJSON, sorted.
+func (pa *PluginAnalyzer) graphDocument() jsonGraph {
+	graph := jsonGraph{Nodes: []jsonNode{}, Edges: []jsonEdge{}}
+
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		graph.Nodes = append(graph.Nodes, jsonNode{Name: plugin.Name, FolderName: plugin.FolderName, IsExternal: plugin.IsExternal})
+		for _, dep := range plugin.Dependencies {
+			if target, ok := pa.Plugins[dep.Name]; !ok || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+			graph.Edges = append(graph.Edges, jsonEdge{From: name, To: dep.Name, Kind: dep.Kind})
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.To != b.To {
+			return a.To < b.To
+		}
+		return kindOrder[a.Kind] < kindOrder[b.Kind]
+	})
+	return graph
+}
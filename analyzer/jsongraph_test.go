@@ -0,0 +1,46 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateJSONRoundTrip(t *testing.T) {
+	composers := map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*", "x/lib": "^1.0"}}`,
+		"B": `{"name": "v/b", "require": {"x/lib": "^1.0"}}`,
+	}
+	pa, _ := scanFixture(t, composers, showExternal)
+
+	data, err := pa.GenerateJSON()
+	if err != nil {
+		t.Fatalf("GenerateJSON: %v", err)
+	}
+	var got jsonGraph
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+	want := jsonGraph{
+		Nodes: []jsonNode{
+			{Name: "v/a", FolderName: "A"},
+			{Name: "v/b", FolderName: "B"},
+			{Name: "x/lib", FolderName: "x/lib", IsExternal: true},
+		},
+		Edges: []jsonEdge{
+			{From: "v/a", To: "v/b", Kind: KindRequire},
+			{From: "v/a", To: "x/lib", Kind: KindRequire},
+			{From: "v/b", To: "x/lib", Kind: KindRequire},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+
+	// A second scan of the same plugins gives the same bytes.
+	again, _ := scanFixture(t, composers, showExternal)
+	if data2, _ := again.GenerateJSON(); !bytes.Equal(data, data2) {
+		t.Errorf("output differs between runs:\n%s\n%s", data, data2)
+	}
+}
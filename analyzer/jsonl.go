@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// jsonlNode and jsonlEdge are the lines written by StreamJSONL.
+type jsonlNode struct {
+	Type string `json:"type"` // "node"
+	jsonNode
+}
+
+type jsonlEdge struct {
+	Type string `json:"type"` // "edge"
+	jsonEdge
+}
+
+// StreamJSONL writes the graph of GenerateJSON as JSON Lines: one object
+// per line, first a {"type":"node",...} line for every node, then a
+// {"type":"edge",...} line for every edge, in the same order as
+// GenerateJSON. Lines are encoded one at a time, so consumers can process
+// the graph as it arrives and the whole document is never held in memory.
+func (pa *PluginAnalyzer) StreamJSONL(w io.Writer) error {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if !plugin.IsExternal || pa.ShowExternalDeps {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		line := jsonlNode{Type: "node", jsonNode: jsonNode{Name: plugin.Name, FolderName: plugin.FolderName, IsExternal: plugin.IsExternal}}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	// Dependencies are sorted by target and kind, so edges come out in
+	// the order GenerateJSON sorts them in.
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		for _, dep := range plugin.Dependencies {
+			if target, ok := pa.Plugins[dep.Name]; !ok || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+			line := jsonlEdge{Type: "edge", jsonEdge: jsonEdge{From: plugin.Name, To: dep.Name, Kind: dep.Kind}}
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		}
+	}
+	return buf.Flush()
+}
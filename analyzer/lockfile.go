@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ComposerLock is the subset of composer.lock the analyzer reads.
+type ComposerLock struct {
+	Packages    []LockedPackage `json:"packages"`
+	PackagesDev []LockedPackage `json:"packages-dev"`
+}
+
+// LockedPackage is a package entry of composer.lock.
+type LockedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Abandoned is true, or the name of the suggested replacement, for
+	// packages their maintainers gave up.
+	Abandoned json.RawMessage `json:"abandoned,omitempty"`
+}
+
+// abandoned reports whether the package is abandoned and which package
+// composer suggests instead, if any.
+func (p LockedPackage) abandoned() (bool, string) {
+	var replacement string
+	if err := json.Unmarshal(p.Abandoned, &replacement); err == nil {
+		return true, replacement
+	}
+	var flag bool
+	if err := json.Unmarshal(p.Abandoned, &flag); err == nil {
+		return flag, ""
+	}
+	return false, ""
+}
+
+// LoadComposerLock reads and parses a composer.lock file.
+func LoadComposerLock(path string) (*ComposerLock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lock ComposerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// Versions returns the locked version of every package, keyed by name.
+func (l *ComposerLock) Versions() map[string]string {
+	versions := make(map[string]string)
+	for _, p := range append(l.Packages, l.PackagesDev...) {
+		versions[p.Name] = p.Version
+	}
+	return versions
+}
+
+// AbandonedPackages returns the abandoned packages of the lock file, each
+// mapped to its suggested replacement or "" if composer names none.
+func (l *ComposerLock) AbandonedPackages() map[string]string {
+	abandoned := make(map[string]string)
+	for _, p := range append(l.Packages, l.PackagesDev...) {
+		if ok, replacement := p.abandoned(); ok {
+			abandoned[normalizePackageName(p.Name)] = replacement
+		}
+	}
+	return abandoned
+}
+
+// AbandonedDependency is an external package required by a plugin that is
+// abandoned according to the project's composer.lock.
+type AbandonedDependency struct {
+	Package     string
+	Replacement string   // suggested by composer, may be empty
+	Users       []string // folders of the plugins requiring it, sorted
+}
+
+// AbandonedDependencies returns the external packages used by internal
+// plugins that are listed in Abandoned, sorted by name. External packages
+// count whether or not they are drawn with ShowExternalDeps.
+func (pa *PluginAnalyzer) AbandonedDependencies() []AbandonedDependency {
+	var abandoned []AbandonedDependency
+	for pkg, users := range pa.externalUsers {
+		replacement, ok := pa.Abandoned[pkg]
+		if !ok {
+			continue
+		}
+		var folders []string
+		for user := range users {
+			if plugin, ok := pa.Plugins[user]; ok {
+				user = plugin.FolderName
+			}
+			folders = append(folders, user)
+		}
+		sort.Strings(folders)
+		abandoned = append(abandoned, AbandonedDependency{Package: pkg, Replacement: replacement, Users: folders})
+	}
+	sort.Slice(abandoned, func(i, j int) bool { return abandoned[i].Package < abandoned[j].Package })
+	return abandoned
+}
+
+// LockDrift is a package whose locked version no longer satisfies the
+// constraint declared in the plugin's composer.json.
+type LockDrift struct {
+	Plugin     string
+	Package    string
+	Constraint string
+	Locked     string
+}
+
+// CompareLocks checks every internal plugin that ships its own composer.lock
+// and returns the packages whose locked version falls outside the declared
+// require or require-dev constraint. Plugins without a lock file are skipped.
+func (pa *PluginAnalyzer) CompareLocks() ([]LockDrift, error) {
+	var drifts []LockDrift
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		lockPath := filepath.Join(filepath.Dir(pa.composerPath(plugin)), "composer.lock")
+		lock, err := LoadComposerLock(lockPath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		locked := lock.Versions()
+		for _, section := range []map[string]string{plugin.Require, plugin.RequireDev} {
+			for pkg, constraintStr := range section {
+				version, ok := locked[pkg]
+				if !ok {
+					continue
+				}
+				c, err := parseConstraint(constraintStr)
+				if err != nil {
+					continue
+				}
+				v, _, err := parseVersion(version)
+				if err != nil {
+					continue // branch versions like dev-main
+				}
+				if !c.allows(v) {
+					drifts = append(drifts, LockDrift{Plugin: plugin.FolderName, Package: pkg, Constraint: constraintStr, Locked: version})
+				}
+			}
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Plugin != drifts[j].Plugin {
+			return drifts[i].Plugin < drifts[j].Plugin
+		}
+		return drifts[i].Package < drifts[j].Package
+	})
+	return drifts, nil
+}
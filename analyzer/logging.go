@@ -0,0 +1,40 @@
+package analyzer
+
+import "log"
+
+// Verbosity selects which diagnostics the package writes to the analyzer's
+// Logger. Results are always returned, never printed.
+type Verbosity int
+
+const (
+	Quiet   Verbosity = iota // errors only
+	Normal                   // plus warnings
+	Verbose                  // plus every file read and edge added
+)
+
+// LogLevel is the verbosity of the package's diagnostics; the command sets
+// it with -quiet and -verbose.
+var LogLevel = Normal
+
+// logger returns the Logger diagnostics go to, the standard logger if none
+// was set.
+func (pa *PluginAnalyzer) logger() *log.Logger {
+	if pa.Logger != nil {
+		return pa.Logger
+	}
+	return log.Default()
+}
+
+// warnf logs a warning unless LogLevel is Quiet.
+func (pa *PluginAnalyzer) warnf(format string, args ...interface{}) {
+	if LogLevel >= Normal {
+		pa.logger().Printf("Warning: "+format, args...)
+	}
+}
+
+// debugf logs a detail of the scan with LogLevel Verbose.
+func (pa *PluginAnalyzer) debugf(format string, args ...interface{}) {
+	if LogLevel >= Verbose {
+		pa.logger().Printf(format, args...)
+	}
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ArchitectureManifest declares the intended internal dependency structure.
+// Plugins may be given by composer or folder name.
+//
+//	dependencies:
+//	  PluginA: [PluginB, PluginC]
+//	forbidden:
+//	  PluginC: [PluginA]
+type ArchitectureManifest struct {
+	Dependencies map[string][]string `yaml:"dependencies"`
+	Forbidden    map[string][]string `yaml:"forbidden"`
+}
+
+// Manifest deviation kinds.
+const (
+	DeviationUndeclared = "undeclared dependency"
+	DeviationStale      = "stale declaration"
+	DeviationForbidden  = "forbidden dependency"
+)
+
+// ManifestDeviation is a difference between the manifest and the scanned
+// dependencies.
+type ManifestDeviation struct {
+	Kind     string
+	From, To string // folder names, or the manifest spelling if unknown
+}
+
+// LoadManifest reads an architecture manifest from a YAML file.
+func LoadManifest(path string) (*ArchitectureManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest ArchitectureManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
+
+// VerifyManifest compares the internal dependencies with the manifest and
+// returns every edge that exists but isn't declared, every declared edge
+// that doesn't exist, and every existing edge the manifest forbids. Suggest
+// edges are not considered. The result is sorted by kind, source and
+// target.
+func (pa *PluginAnalyzer) VerifyManifest(manifest *ArchitectureManifest) []ManifestDeviation {
+	folder := func(name string) string {
+		if plugin := pa.FindPlugin(name); plugin != nil {
+			return plugin.FolderName
+		}
+		return name
+	}
+	edgeSet := func(section map[string][]string) map[[2]string]bool {
+		set := make(map[[2]string]bool)
+		for from, targets := range section {
+			for _, to := range targets {
+				set[[2]string{folder(from), folder(to)}] = true
+			}
+		}
+		return set
+	}
+	declared := edgeSet(manifest.Dependencies)
+	forbidden := edgeSet(manifest.Forbidden)
+
+	actual := make(map[[2]string]bool)
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		for _, dep := range pa.internalDependencies(plugin) {
+			actual[[2]string{plugin.FolderName, pa.Plugins[dep].FolderName}] = true
+		}
+	}
+
+	var deviations []ManifestDeviation
+	for edge := range actual {
+		switch {
+		case forbidden[edge]:
+			deviations = append(deviations, ManifestDeviation{Kind: DeviationForbidden, From: edge[0], To: edge[1]})
+		case !declared[edge]:
+			deviations = append(deviations, ManifestDeviation{Kind: DeviationUndeclared, From: edge[0], To: edge[1]})
+		}
+	}
+	for edge := range declared {
+		if !actual[edge] {
+			deviations = append(deviations, ManifestDeviation{Kind: DeviationStale, From: edge[0], To: edge[1]})
+		}
+	}
+
+	sort.Slice(deviations, func(i, j int) bool {
+		a, b := deviations[i], deviations[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+	return deviations
+}
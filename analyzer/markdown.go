@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// markdownCell escapes a value for use in a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// GenerateMarkdown returns a Markdown report with the Mermaid graph in a
+// code block that GitHub and GitLab render, a table of the internal plugins
+// with their versions and dependency counts, and the external packages with
+// the number of plugins using them.
+func (pa *PluginAnalyzer) GenerateMarkdown() string {
+	var sb strings.Builder
+	if pa.Title != "" {
+		fmt.Fprintf(&sb, "# %s\n\n", pa.titleText())
+	} else {
+		sb.WriteString("# Plugin Dependencies\n\n")
+	}
+
+	sb.WriteString("```mermaid\n")
+	sb.WriteString(pa.GenerateMermaid())
+	sb.WriteString("```\n\n")
+
+	metrics := pa.Metrics()
+	sb.WriteString("## Plugins\n\n")
+	sb.WriteString("| Plugin | Package | Version | Dependencies | Dependents |\n")
+	sb.WriteString("| --- | --- | --- | ---: | ---: |\n")
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal {
+			continue
+		}
+		fmt.Fprintf(&sb, "| %s | `%s` | %s | %d | %d |\n",
+			markdownCell(plugin.FolderName), plugin.Name, markdownCell(VersionLabel(plugin)),
+			len(plugin.Dependencies), metrics[plugin.Name].FanIn)
+	}
+
+	sb.WriteString("\n## External Dependencies\n\n")
+	if len(pa.ExternalDepsCount) == 0 {
+		sb.WriteString("none\n")
+	}
+	deps := make([]string, 0, len(pa.ExternalDepsCount))
+	for dep := range pa.ExternalDepsCount {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	for _, dep := range deps {
+		fmt.Fprintf(&sb, "- `%s`: used by %d plugin(s)\n", dep, pa.ExternalDepsCount[dep])
+	}
+
+	return sb.String()
+}
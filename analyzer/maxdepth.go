@@ -0,0 +1,91 @@
+package analyzer
+
+// rootDistances returns the number of hops from the nearest root, a plugin
+// without internal dependents, to every internal plugin. Plugins only
+// reachable through a cycle count as roots themselves, taken in name order.
+func (pa *PluginAnalyzer) rootDistances() map[string]int {
+	dist := make(map[string]int)
+	metrics := pa.Metrics()
+
+	var frontier []string
+	for _, name := range pa.InternalPluginNames() {
+		if metrics[name].FanIn == 0 {
+			dist[name] = 0
+			frontier = append(frontier, name)
+		}
+	}
+	spread := func(frontier []string) {
+		for len(frontier) > 0 {
+			var next []string
+			for _, name := range frontier {
+				for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+					if _, seen := dist[dep]; !seen {
+						dist[dep] = dist[name] + 1
+						next = append(next, dep)
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+	spread(frontier)
+	for _, name := range pa.InternalPluginNames() {
+		if _, seen := dist[name]; !seen {
+			dist[name] = 0
+			spread([]string{name})
+		}
+	}
+	return dist
+}
+
+// LimitDepth returns a copy of the analyzer without the plugins more than
+// maxDepth hops below the nearest root; roots are at depth 0. External
+// packages are kept when a kept plugin above maxDepth requires them. With
+// placeholders, every plugin at maxDepth whose dependencies were cut gets an
+// edge to a "..." node instead.
+func (pa *PluginAnalyzer) LimitDepth(maxDepth int, placeholders bool) *PluginAnalyzer {
+	dist := pa.rootDistances()
+	keep := make(map[string]bool)
+	for name, d := range dist {
+		if d > maxDepth {
+			continue
+		}
+		keep[name] = true
+		if d == maxDepth {
+			continue
+		}
+		for _, dep := range pa.Plugins[name].Dependencies {
+			if pa.Plugins[dep.Name].IsExternal {
+				keep[dep.Name] = true
+			}
+		}
+	}
+	sub := pa.subset(keep)
+
+	if placeholders {
+		for name, d := range dist {
+			if d != maxDepth {
+				continue
+			}
+			cut := 0
+			for _, dep := range pa.Plugins[name].Dependencies {
+				if !keep[dep.Name] && (!pa.Plugins[dep.Name].IsExternal || pa.ShowExternalDeps) {
+					cut++
+				}
+			}
+			if cut == 0 {
+				continue
+			}
+			plugin := sub.Plugins[name]
+			placeholder := &Plugin{
+				Name:       name + " ...",
+				FolderName: plugin.FolderName + " ...",
+				Type:       plugin.Type,
+				Truncated:  cut,
+			}
+			sub.Plugins[placeholder.Name] = placeholder
+			plugin.Dependencies = append(plugin.Dependencies, Dependency{Name: placeholder.Name, Kind: KindRequire})
+		}
+	}
+	return sub
+}
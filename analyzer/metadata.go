@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+)
+
+// pluginMetaFile is the optional sidecar file next to a plugin's
+// composer.json holding free-form organizational metadata such as
+// {"domain": "checkout", "team": "core"}.
+const pluginMetaFile = "plugin-meta.json"
+
+// loadPluginMetadata reads the metadata sidecar of the plugin in folder, a
+// slash-separated path within fsys. A missing sidecar is not an error and
+// yields nil.
+func loadPluginMetadata(fsys fs.FS, folder string) (map[string]string, error) {
+	data, err := fs.ReadFile(fsys, path.Join(folder, pluginMetaFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var meta map[string]string
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", pluginMetaFile, err)
+	}
+	return meta, nil
+}
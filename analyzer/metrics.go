@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PluginMetrics holds the coupling metrics of a single plugin.
+type PluginMetrics struct {
+	Name       string
+	FolderName string
+	FanIn      int // number of internal plugins depending on this plugin
+	FanOut     int // number of internal plugins this plugin depends on
+}
+
+// internalDependencies returns the sorted, de-duplicated names of the
+// internal plugins the given plugin depends on. Optional suggest edges
+// are not considered.
+func (pa *PluginAnalyzer) internalDependencies(plugin *Plugin) []string {
+	seen := make(map[string]bool)
+	var deps []string
+	for _, dep := range plugin.Dependencies {
+		if dep.Kind == KindSuggest {
+			continue
+		}
+		depPlugin, ok := pa.Plugins[dep.Name]
+		if !ok || depPlugin.IsExternal || seen[dep.Name] {
+			continue
+		}
+		seen[dep.Name] = true
+		deps = append(deps, dep.Name)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// metricDependencies returns the dependencies counted by Metrics: the
+// internal ones, plus the external nodes of the graph with
+// MetricsIncludeExternal.
+func (pa *PluginAnalyzer) metricDependencies(plugin *Plugin) []string {
+	deps := pa.internalDependencies(plugin)
+	if !pa.MetricsIncludeExternal {
+		return deps
+	}
+	seen := make(map[string]bool)
+	for _, dep := range plugin.Dependencies {
+		if dep.Kind != KindSuggest && pa.Plugins[dep.Name].IsExternal && !seen[dep.Name] {
+			seen[dep.Name] = true
+			deps = append(deps, dep.Name)
+		}
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// Metrics computes fan-in and fan-out for every internal plugin, keyed by
+// composer name. By default only edges between internal plugins are counted,
+// so framework requirements don't dominate the numbers; with
+// MetricsIncludeExternal, edges to external nodes count too and the external
+// nodes get an entry with their fan-in.
+func (pa *PluginAnalyzer) Metrics() map[string]PluginMetrics {
+	metrics := make(map[string]PluginMetrics)
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.MetricsIncludeExternal {
+			continue
+		}
+		metrics[name] = PluginMetrics{Name: name, FolderName: plugin.FolderName}
+	}
+
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal {
+			continue
+		}
+		for _, dep := range pa.metricDependencies(plugin) {
+			m := metrics[name]
+			m.FanOut++
+			metrics[name] = m
+
+			d := metrics[dep]
+			d.FanIn++
+			metrics[dep] = d
+		}
+	}
+
+	return metrics
+}
+
+// CouplingReport returns the metrics of every plugin sorted by fan-in,
+// highest first, so the plugins whose breakage cascades furthest lead.
+// Ties are sorted by fan-out, highest first, and then by folder name.
+func (pa *PluginAnalyzer) CouplingReport() []PluginMetrics {
+	var report []PluginMetrics
+	for _, m := range pa.Metrics() {
+		report = append(report, m)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].FanIn != report[j].FanIn {
+			return report[i].FanIn > report[j].FanIn
+		}
+		if report[i].FanOut != report[j].FanOut {
+			return report[i].FanOut > report[j].FanOut
+		}
+		return report[i].FolderName < report[j].FolderName
+	})
+
+	return report
+}
+
+// CouplingHotspots returns the plugins whose fan-in exceeds minFanIn and
+// whose fan-out exceeds minFanOut at the same time, sorted by combined
+// coupling (highest first) and then by folder name.
+func (pa *PluginAnalyzer) CouplingHotspots(minFanIn, minFanOut int) []PluginMetrics {
+	var hotspots []PluginMetrics
+	for _, m := range pa.Metrics() {
+		if m.FanIn > minFanIn && m.FanOut > minFanOut {
+			hotspots = append(hotspots, m)
+		}
+	}
+
+	sort.Slice(hotspots, func(i, j int) bool {
+		ci := hotspots[i].FanIn + hotspots[i].FanOut
+		cj := hotspots[j].FanIn + hotspots[j].FanOut
+		if ci != cj {
+			return ci > cj
+		}
+		return hotspots[i].FolderName < hotspots[j].FolderName
+	})
+
+	return hotspots
+}
+
+// TransitiveDependencies returns the sorted composer names of all internal
+// plugins reachable from the named plugin, excluding the plugin itself.
+// Cycles are handled by tracking visited nodes.
+func (pa *PluginAnalyzer) TransitiveDependencies(name string) []string {
+	visited := map[string]bool{name: true}
+	var closure []string
+
+	var visit func(current string)
+	visit = func(current string) {
+		for _, dep := range pa.internalDependencies(pa.Plugins[current]) {
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			closure = append(closure, dep)
+			visit(dep)
+		}
+	}
+	visit(name)
+
+	sort.Strings(closure)
+	return closure
+}
+
+// Closure returns the sorted folder names of all internal plugins the named
+// plugin (folder or composer name) pulls in directly or transitively.
+func (pa *PluginAnalyzer) Closure(name string) ([]string, error) {
+	plugin := pa.FindPlugin(name)
+	if plugin == nil {
+		return nil, fmt.Errorf("unknown plugin %q", name)
+	}
+
+	names := pa.TransitiveDependencies(plugin.Name)
+	folders := make([]string, 0, len(names))
+	for _, n := range names {
+		folders = append(folders, pa.Plugins[n].FolderName)
+	}
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// Leaves returns the sorted folder names of the internal plugins that depend
+// on no other internal plugin. External and platform requirements as well as
+// suggest edges don't count.
+func (pa *PluginAnalyzer) Leaves() []string {
+	var leaves []string
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		if len(pa.internalDependencies(plugin)) == 0 {
+			leaves = append(leaves, plugin.FolderName)
+		}
+	}
+	sort.Strings(leaves)
+	return leaves
+}
+
+// Roots returns the sorted folder names of the internal plugins no other
+// internal plugin depends on, the entry points of the graph: those with a
+// fan-in of zero. Suggest edges don't count.
+func (pa *PluginAnalyzer) Roots() []string {
+	metrics := pa.Metrics()
+	var roots []string
+	for _, name := range pa.InternalPluginNames() {
+		if metrics[name].FanIn == 0 {
+			roots = append(roots, pa.Plugins[name].FolderName)
+		}
+	}
+	sort.Strings(roots)
+	return roots
+}
+
+// Orphans returns the sorted folder names of the internal plugins that
+// neither depend on another internal plugin nor are depended upon by one.
+// Such plugins could be extracted or removed without affecting the rest.
+func (pa *PluginAnalyzer) Orphans() []string {
+	related := make(map[string]bool)
+	for _, name := range pa.InternalPluginNames() {
+		for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+			related[name] = true
+			related[dep] = true
+		}
+	}
+
+	var orphans []string
+	for _, name := range pa.InternalPluginNames() {
+		if !related[name] {
+			orphans = append(orphans, pa.Plugins[name].FolderName)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
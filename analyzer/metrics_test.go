@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// metricsFixture is A → B, C; B → D; C → D, with every plugin also
+// requiring an external package.
+var metricsFixture = map[string]string{
+	"A": `{"name": "v/a", "require": {"v/b": "*", "v/c": "*", "x/lib": "*"}}`,
+	"B": `{"name": "v/b", "require": {"v/d": "*", "x/lib": "*"}}`,
+	"C": `{"name": "v/c", "require": {"v/d": "*", "x/lib": "*"}}`,
+	"D": `{"name": "v/d", "require": {"x/lib": "*"}}`,
+}
+
+func TestMetrics(t *testing.T) {
+	pa, _ := scanFixture(t, metricsFixture, showExternal)
+	want := map[string]PluginMetrics{
+		"v/a": {Name: "v/a", FolderName: "A", FanIn: 0, FanOut: 2},
+		"v/b": {Name: "v/b", FolderName: "B", FanIn: 1, FanOut: 1},
+		"v/c": {Name: "v/c", FolderName: "C", FanIn: 1, FanOut: 1},
+		"v/d": {Name: "v/d", FolderName: "D", FanIn: 2, FanOut: 0},
+	}
+	if got := pa.Metrics(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+
+	pa.MetricsIncludeExternal = true
+	metrics := pa.Metrics()
+	if got := metrics["x/lib"].FanIn; got != 4 {
+		t.Errorf("fan-in of x/lib = %d, want 4", got)
+	}
+	if got := metrics["v/a"].FanOut; got != 3 {
+		t.Errorf("fan-out of v/a with external edges = %d, want 3", got)
+	}
+}
+
+func TestCouplingReportOrder(t *testing.T) {
+	pa, _ := scanFixture(t, metricsFixture)
+	var folders []string
+	for _, m := range pa.CouplingReport() {
+		folders = append(folders, m.FolderName)
+	}
+	if want := []string{"D", "B", "C", "A"}; !reflect.DeepEqual(folders, want) {
+		t.Errorf("CouplingReport() order = %q, want %q", folders, want)
+	}
+}
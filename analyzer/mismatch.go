@@ -0,0 +1,65 @@
+package analyzer
+
+import "sort"
+
+// VersionMismatch is an internal dependency whose constraint admits no
+// version of the major version family the target plugin is at.
+type VersionMismatch struct {
+	Plugin     string // folder of the depending plugin
+	Target     string // folder of the required plugin
+	Constraint string
+	Version    string // version declared by the target
+}
+
+// majorMismatch reports whether dep points at an internal plugin whose
+// declared major version the constraint cannot be satisfied within, e.g.
+// "^1.0" on a plugin at 2.3.0. Edges without a parsable constraint or
+// target version are not flagged.
+func (pa *PluginAnalyzer) majorMismatch(dep Dependency) bool {
+	target, ok := pa.Plugins[dep.Name]
+	if !ok || target.IsExternal || target.Version == "" || dep.Constraint == "" {
+		return false
+	}
+	v, _, err := parseVersion(target.Version)
+	if err != nil {
+		return false
+	}
+	c, err := parseConstraint(dep.Constraint)
+	if err != nil {
+		return false
+	}
+
+	family := constraint{{
+		min: version{v[0], 0, 0}, hasMin: true, minIncl: true,
+		max: version{v[0] + 1, 0, 0}, hasMax: true,
+	}}
+	return !c.intersect(family).satisfiable()
+}
+
+// VersionMismatches returns the internal edges flagged by majorMismatch,
+// sorted by depending plugin and target.
+func (pa *PluginAnalyzer) VersionMismatches() []VersionMismatch {
+	var mismatches []VersionMismatch
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		for _, dep := range plugin.Dependencies {
+			if pa.majorMismatch(dep) {
+				target := pa.Plugins[dep.Name]
+				mismatches = append(mismatches, VersionMismatch{
+					Plugin:     plugin.FolderName,
+					Target:     target.FolderName,
+					Constraint: dep.Constraint,
+					Version:    target.Version,
+				})
+			}
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		if mismatches[i].Plugin != mismatches[j].Plugin {
+			return mismatches[i].Plugin < mismatches[j].Plugin
+		}
+		return mismatches[i].Target < mismatches[j].Target
+	})
+	return mismatches
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import "sort"
+
+// MutualDependency is a pair of internal plugins depending on each other
+// directly, the shortest possible cycle.
+type MutualDependency struct {
+	A, B string // folder names, A sorting first
+}
+
+// mutualPairs returns the composer names of the plugins in a mutual
+// dependency, mapped to their partners.
+func (pa *PluginAnalyzer) mutualPairs() map[string]map[string]bool {
+	deps := make(map[string]map[string]bool)
+	for _, name := range pa.InternalPluginNames() {
+		deps[name] = make(map[string]bool)
+		for _, dep := range pa.internalDependencies(pa.Plugins[name]) {
+			deps[name][dep] = true
+		}
+	}
+
+	pairs := make(map[string]map[string]bool)
+	for name, targets := range deps {
+		for dep := range targets {
+			if dep == name || !deps[dep][name] {
+				continue
+			}
+			if pairs[name] == nil {
+				pairs[name] = make(map[string]bool)
+			}
+			pairs[name][dep] = true
+		}
+	}
+	return pairs
+}
+
+// pairKey returns a key for the unordered pair of two names.
+func pairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "\x00" + b
+}
+
+// MutualDependencies returns the pairs of internal plugins that require each
+// other directly, sorted by folder names. Suggest entries don't count.
+func (pa *PluginAnalyzer) MutualDependencies() []MutualDependency {
+	var mutual []MutualDependency
+	for name, partners := range pa.mutualPairs() {
+		for partner := range partners {
+			a, b := pa.Plugins[name].FolderName, pa.Plugins[partner].FolderName
+			if a < b {
+				mutual = append(mutual, MutualDependency{A: a, B: b})
+			}
+		}
+	}
+	sort.Slice(mutual, func(i, j int) bool {
+		if mutual[i].A != mutual[j].A {
+			return mutual[i].A < mutual[j].A
+		}
+		return mutual[i].B < mutual[j].B
+	})
+	return mutual
+}
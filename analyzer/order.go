@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// InstallOrder returns the folder names of the internal plugins ordered so
+// that every plugin comes after the plugins it depends on. Plugins that don't
+// depend on each other are ordered by folder name. It fails if a dependency
+// cycle makes such an order impossible.
+func (pa *PluginAnalyzer) InstallOrder() ([]string, error) {
+	order, err := pa.installOrder()
+	folders := make([]string, len(order))
+	for i, name := range order {
+		folders[i] = pa.Plugins[name].FolderName
+	}
+	return folders, err
+}
+
+// installOrder is InstallOrder returning composer names.
+func (pa *PluginAnalyzer) installOrder() ([]string, error) {
+	names := pa.InternalPluginNames()
+	pending := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, name := range names {
+		deps := pa.internalDependencies(pa.Plugins[name])
+		pending[name] = len(deps)
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	var ready []string
+	for _, name := range names {
+		if pending[name] == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	var order []string
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			return pa.Plugins[ready[i]].FolderName < pa.Plugins[ready[j]].FolderName
+		})
+		current := ready[0]
+		ready = ready[1:]
+		order = append(order, current)
+		for _, dependent := range dependents[current] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(names) {
+		var stuck []string
+		for _, name := range names {
+			if pending[name] > 0 {
+				stuck = append(stuck, pa.Plugins[name].FolderName)
+			}
+		}
+		return order, fmt.Errorf("no install order exists, dependency cycle among: %s", strings.Join(stuck, ", "))
+	}
+	return order, nil
+}
+
+// technicalName returns the name Shopware knows a plugin by: the short name
+// of its plugin class, or the folder name when composer.json doesn't
+// declare extra.shopware-plugin-class.
+func technicalName(plugin *Plugin) string {
+	if plugin.PluginClass == "" {
+		return plugin.FolderName
+	}
+	class := plugin.PluginClass
+	if i := strings.LastIndex(class, `\`); i >= 0 {
+		class = class[i+1:]
+	}
+	return class
+}
+
+// GenerateInstallScripts returns shell scripts installing and activating the
+// internal plugins in dependency order and uninstalling them in reverse.
+func (pa *PluginAnalyzer) GenerateInstallScripts() (install, uninstall string, err error) {
+	order, err := pa.installOrder()
+	if err != nil {
+		return "", "", err
+	}
+
+	script := func(action string, names []string) string {
+		var sb strings.Builder
+		sb.WriteString("#!/bin/sh\n")
+		sb.WriteString("# Generated by sw6-plugin-analyzer; run from the Shopware root.\n")
+		sb.WriteString("set -e\n\n")
+		for _, name := range names {
+			sb.WriteString(fmt.Sprintf("bin/console %s %s\n", action, technicalName(pa.Plugins[name])))
+		}
+		return sb.String()
+	}
+
+	reversed := make([]string, len(order))
+	for i, name := range order {
+		reversed[len(order)-1-i] = name
+	}
+	return script("plugin:install --activate", order), script("plugin:uninstall", reversed), nil
+}
+
+// UninstallScriptPath derives the companion uninstall script name from the
+// install script path: install.sh becomes uninstall.sh, deploy.sh becomes
+// deploy-uninstall.sh.
+func UninstallScriptPath(installPath string) string {
+	dir, base := filepath.Split(installPath)
+	if strings.Contains(base, "install") {
+		return filepath.Join(dir, strings.Replace(base, "install", "uninstall", 1))
+	}
+	ext := filepath.Ext(base)
+	return filepath.Join(dir, strings.TrimSuffix(base, ext)+"-uninstall"+ext)
+}
+
+// DuplicateClass is a shopware-plugin-class declared by several plugins.
+type DuplicateClass struct {
+	Class   string
+	Plugins []string // folder names, sorted
+}
+
+// DuplicatePluginClasses returns the plugin classes declared by more than
+// one internal plugin, sorted by class. Shopware identifies plugins by this
+// class, so only one of them could be installed.
+func (pa *PluginAnalyzer) DuplicatePluginClasses() []DuplicateClass {
+	byClass := make(map[string][]string)
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		if plugin.PluginClass != "" {
+			byClass[plugin.PluginClass] = append(byClass[plugin.PluginClass], plugin.FolderName)
+		}
+	}
+
+	var duplicates []DuplicateClass
+	for class, folders := range byClass {
+		if len(folders) > 1 {
+			sort.Strings(folders)
+			duplicates = append(duplicates, DuplicateClass{Class: class, Plugins: folders})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Class < duplicates[j].Class })
+	return duplicates
+}
+
+// DuplicateName is a composer name declared by several plugin folders.
+type DuplicateName struct {
+	Name    string
+	Kept    string   // folder of the plugin that is analyzed
+	Ignored []string // folders skipped because they declare the name again
+}
+
+// DuplicatePluginNames returns the composer names declared by more than one
+// scanned folder, sorted by name. The first folder scanned wins: plugins
+// directories in the given order, folders sorted by name within each.
+func (pa *PluginAnalyzer) DuplicatePluginNames() []DuplicateName {
+	var duplicates []DuplicateName
+	for name, ignored := range pa.duplicates {
+		kept := pa.Plugins[name]
+		if kept == nil {
+			continue // scoped out
+		}
+		duplicates = append(duplicates, DuplicateName{Name: name, Kept: filepath.Join(kept.Dir, kept.FolderPath), Ignored: ignored})
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Name < duplicates[j].Name })
+	return duplicates
+}
@@ -0,0 +1,19 @@
+package analyzer
+
+import "encoding/json"
+
+// GenerateOTelAttributes returns the internal plugin set as a flat JSON
+// object of OpenTelemetry resource attributes, one "plugin.<name>.version"
+// key per plugin. Plugins without a declared version are reported as
+// "unknown".
+func (pa *PluginAnalyzer) GenerateOTelAttributes() ([]byte, error) {
+	attributes := make(map[string]string)
+	for _, name := range pa.InternalPluginNames() {
+		version := pa.Plugins[name].Version
+		if version == "" {
+			version = "unknown"
+		}
+		attributes["plugin."+name+".version"] = version
+	}
+	return json.MarshalIndent(attributes, "", "  ")
+}
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"os"
+	"runtime"
+	"sync"
+)
+
+// forEachParallel calls fn for every index below n on up to workers
+// goroutines and returns when all calls are done. workers below 1 means
+// one per CPU.
+func forEachParallel(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
+
+// composerResult is the outcome of reading one plugin folder's
+// composer.json during the parallel part of a scan.
+type composerResult struct {
+	entry    cacheEntry
+	cached   bool
+	missing  bool // there is no composer.json
+	parseErr bool // err happened while parsing rather than reading
+	err      error
+}
+
+// readComposers reads the composer.json files at paths concurrently with
+// Workers goroutines, reusing unchanged files from the scan cache. Results
+// are in the order of paths. The cache is only read here, so the workers
+// need no locking; callers add fresh results to it afterwards.
+func (pa *PluginAnalyzer) readComposers(paths []string) []composerResult {
+	results := make([]composerResult, len(paths))
+	forEachParallel(len(paths), pa.Workers, func(i int) {
+		res := &results[i]
+		if _, err := os.Stat(paths[i]); os.IsNotExist(err) {
+			res.missing = true
+			return
+		}
+		if res.entry, res.cached = pa.cache.get(paths[i]); res.cached {
+			return
+		}
+		res.entry.Composer, res.entry.Encoding, res.parseErr, res.err = pa.readComposer(paths[i])
+	})
+	return results
+}
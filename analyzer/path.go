@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindPath returns the shortest chain of internal dependencies leading from
+// one plugin to another, both given by folder or composer name, as composer
+// names including both ends. Dependencies are visited in sorted order, so
+// among several shortest paths the same one is returned on every run. The
+// second return value is false if either plugin is unknown or no path
+// exists.
+func (pa *PluginAnalyzer) FindPath(from, to string) ([]string, bool) {
+	start, end := pa.FindPlugin(from), pa.FindPlugin(to)
+	if start == nil || end == nil {
+		return nil, false
+	}
+
+	previous := map[string]string{start.Name: ""}
+	queue := []string{start.Name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == end.Name {
+			var path []string
+			for name := current; name != ""; name = previous[name] {
+				path = append([]string{name}, path...)
+			}
+			return path, true
+		}
+		for _, dep := range pa.internalDependencies(pa.Plugins[current]) {
+			if _, seen := previous[dep]; !seen {
+				previous[dep] = current
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil, false
+}
+
+// ParsePathFlag splits a -path value of the form "from:to".
+func ParsePathFlag(value string) (from, to string, err error) {
+	from, to, ok := strings.Cut(value, ":")
+	if !ok || from == "" || to == "" {
+		return "", "", fmt.Errorf("invalid -path value %q: expected from:to", value)
+	}
+	return from, to, nil
+}
+
+// onHighlightedPath reports whether the plugin lies on HighlightPath.
+func (pa *PluginAnalyzer) onHighlightedPath(name string) bool {
+	for _, node := range pa.HighlightPath {
+		if node == name {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightedEdge reports whether the edge from -> to is a step of
+// HighlightPath.
+func (pa *PluginAnalyzer) highlightedEdge(from, to string) bool {
+	for i := 1; i < len(pa.HighlightPath); i++ {
+		if pa.HighlightPath[i-1] == from && pa.HighlightPath[i] == to {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatPath joins the folder names of a path of composer names.
+func (pa *PluginAnalyzer) FormatPath(path []string) string {
+	folders := make([]string, len(path))
+	for i, name := range path {
+		folders[i] = pa.Plugins[name].FolderName
+	}
+	return strings.Join(folders, " -> ")
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// GeneratePDFReport renders the graph together with the internal and
+// external dependency summaries as one PDF through Graphviz. The summaries
+// are HTML-like table nodes ranked below the graph, so no PDF library is
+// needed.
+func (pa *PluginAnalyzer) GeneratePDFReport(outputPath string) error {
+	var dot bytes.Buffer
+	if err := pa.GenerateDOT(&dot); err != nil {
+		return fmt.Errorf("failed to write DOT content: %w", err)
+	}
+	// Reopen the graph to append the summary tables before its closing brace.
+	if bytes.HasSuffix(dot.Bytes(), []byte("}\n")) {
+		dot.Truncate(dot.Len() - len("}\n"))
+	}
+	pa.writeDOTSummaryTables(&dot)
+	dot.WriteString("}\n")
+
+	if err := pa.runLayout(&dot, nil, "-Tpdf", "-o", outputPath); err != nil {
+		return err
+	}
+	return verifyGraphvizOutput(outputPath, false)
+}
+
+// writeDOTSummaryTables writes the internal and external dependency
+// summaries as plaintext nodes with HTML-like table labels, on the last rank.
+func (pa *PluginAnalyzer) writeDOTSummaryTables(w io.Writer) {
+	var internal [][]string
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		var deps []string
+		for _, dep := range plugin.Dependencies {
+			target := pa.Plugins[dep.Name]
+			label := target.FolderName
+			if target.IsExternal {
+				label = dep.Name + " (external)"
+			}
+			if dep.Kind != KindRequire {
+				label += fmt.Sprintf(" (%s)", dep.Kind)
+			}
+			deps = append(deps, html.EscapeString(label))
+		}
+		sort.Strings(deps)
+		version := plugin.Version
+		if version == "" {
+			version = "-"
+		}
+		if len(deps) == 0 {
+			deps = []string{"-"}
+		}
+		internal = append(internal, []string{html.EscapeString(plugin.FolderName), html.EscapeString(version), strings.Join(deps, "<BR/>")})
+	}
+
+	var packages []string
+	for pkg := range pa.ExternalDepsCount {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	var external [][]string
+	for _, pkg := range packages {
+		external = append(external, []string{html.EscapeString(pkg), fmt.Sprint(pa.ExternalDepsCount[pkg])})
+	}
+
+	fmt.Fprintln(w, "    newrank=true;")
+	fmt.Fprintln(w, "    { rank=sink;")
+	writeDOTTable(w, "__internal_summary", "Internal Dependencies", []string{"Plugin", "Version", "Depends on"}, internal)
+	if len(external) > 0 {
+		writeDOTTable(w, "__external_summary", "External Dependencies", []string{"Package", "Used by"}, external)
+	}
+	fmt.Fprintln(w, "    }")
+}
+
+// writeDOTTable writes a plaintext node whose label is a table with a title
+// row, a header row and the given rows. Cells are written as they are, so
+// callers escape them; the <BR/> line breaks in the internal summary are the
+// only markup.
+func writeDOTTable(w io.Writer, id, title string, header []string, rows [][]string) {
+	var sb strings.Builder
+	sb.WriteString(`<TABLE BORDER="0" CELLBORDER="1" CELLSPACING="0" CELLPADDING="4">`)
+	sb.WriteString(fmt.Sprintf(`<TR><TD COLSPAN="%d" BGCOLOR="#dddddd"><B>%s</B></TD></TR>`, len(header), html.EscapeString(title)))
+	sb.WriteString("<TR>")
+	for _, h := range header {
+		sb.WriteString(fmt.Sprintf(`<TD BGCOLOR="#f0f0f0"><B>%s</B></TD>`, html.EscapeString(h)))
+	}
+	sb.WriteString("</TR>")
+	for _, row := range rows {
+		sb.WriteString("<TR>")
+		for _, cell := range row {
+			sb.WriteString(fmt.Sprintf(`<TD ALIGN="LEFT" BALIGN="LEFT">%s</TD>`, cell))
+		}
+		sb.WriteString("</TR>")
+	}
+	sb.WriteString("</TABLE>")
+	fmt.Fprintf(w, "        \"%s\" [shape=plaintext, style=\"\", label=<%s>];\n", id, sb.String())
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import "fmt"
+
+// Deployment plan actions.
+const (
+	PlanUninstall = "uninstall"
+	PlanInstall   = "install"
+	PlanUpdate    = "update"
+)
+
+// PlanOperation is one step of a deployment plan.
+type PlanOperation struct {
+	Action string
+	Plugin *Plugin
+	From   string // version before an update or uninstall
+	To     string // version after an update or install
+}
+
+// ScanState returns an analyzer with the configuration of pa that scanned
+// the plugins in dir instead, e.g. a checkout of the deployed release.
+func (pa *PluginAnalyzer) ScanState(dir string) (*PluginAnalyzer, error) {
+	state := *pa
+	state.PluginsDirs = []string{dir}
+	return state.Rescan()
+}
+
+// DeploymentPlan returns the operations turning the plugin set of previous
+// into the one of pa: uninstalls of removed plugins first, dependents before
+// their dependencies, then installs of new plugins and updates of plugins
+// whose version changed, dependencies before their dependents. Plugins are
+// matched by composer name. It fails if a cycle in either state prevents an
+// order.
+func (pa *PluginAnalyzer) DeploymentPlan(previous *PluginAnalyzer) ([]PlanOperation, error) {
+	oldOrder, err := previous.installOrder()
+	if err != nil {
+		return nil, fmt.Errorf("previous state: %w", err)
+	}
+	newOrder, err := pa.installOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	var plan []PlanOperation
+	for i := len(oldOrder) - 1; i >= 0; i-- {
+		old := previous.Plugins[oldOrder[i]]
+		if current, ok := pa.Plugins[old.Name]; !ok || current.IsExternal {
+			plan = append(plan, PlanOperation{Action: PlanUninstall, Plugin: old, From: old.Version})
+		}
+	}
+	for _, name := range newOrder {
+		current := pa.Plugins[name]
+		old, ok := previous.Plugins[name]
+		switch {
+		case !ok || old.IsExternal:
+			plan = append(plan, PlanOperation{Action: PlanInstall, Plugin: current, To: current.Version})
+		case old.Version != current.Version:
+			plan = append(plan, PlanOperation{Action: PlanUpdate, Plugin: current, From: old.Version, To: current.Version})
+		}
+	}
+	return plan, nil
+}
+
+// String renders the operation for the console, e.g.
+// "update PluginA 1.2.0 → 1.3.0".
+func (op PlanOperation) String() string {
+	switch op.Action {
+	case PlanUpdate:
+		return fmt.Sprintf("%s %s %s → %s", op.Action, op.Plugin.FolderName, versionOrNone(op.From), versionOrNone(op.To))
+	case PlanInstall:
+		return fmt.Sprintf("%s %s %s", op.Action, op.Plugin.FolderName, versionOrNone(op.To))
+	default:
+		return fmt.Sprintf("%s %s %s", op.Action, op.Plugin.FolderName, versionOrNone(op.From))
+	}
+}
+
+// versionOrNone returns v, or "(no version)" if it is empty.
+func versionOrNone(v string) string {
+	if v == "" {
+		return "(no version)"
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// plantUMLString quotes s as a PlantUML string.
+func plantUMLString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `'`) + `"`
+}
+
+// GeneratePlantUML returns the graph as a PlantUML component diagram. Each
+// plugin is a component labeled with its folder name; external packages are
+// colored #LightCoral like in the Graphviz output. require edges are solid
+// arrows, require-dev and suggest edges dotted ones.
+func (pa *PluginAnalyzer) GeneratePlantUML() string {
+	var plugins []*Plugin
+	var names []string
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		plugins = append(plugins, plugin)
+		names = append(names, plugin.Name)
+	}
+	ids := structurizrIDs(names)
+
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	if pa.Title != "" {
+		sb.WriteString(fmt.Sprintf("title %s\n", pa.titleText()))
+	}
+	for _, plugin := range plugins {
+		color := ""
+		if plugin.IsExternal {
+			color = " #LightCoral"
+		}
+		sb.WriteString(fmt.Sprintf("component %s as %s%s\n", plantUMLString(plugin.FolderName), ids[plugin.Name], color))
+	}
+
+	for _, plugin := range plugins {
+		for _, edge := range pa.renderedEdges(plugin) {
+			target, ok := ids[edge.Target]
+			if !ok {
+				continue
+			}
+			arrow := "-->"
+			if edge.Kinds[0] != KindRequire {
+				arrow = "..>"
+			}
+			label := ""
+			if badge := edge.badge(); badge != "" {
+				label = " : " + badge
+			}
+			sb.WriteString(fmt.Sprintf("%s %s %s%s\n", ids[plugin.Name], arrow, target, label))
+		}
+	}
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
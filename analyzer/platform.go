@@ -0,0 +1,42 @@
+package analyzer
+
+import "strings"
+
+// isPlatformPackage reports whether name refers to a composer platform
+// package (the PHP runtime, extensions, system libraries or composer APIs)
+// rather than an installable package.
+func isPlatformPackage(name string) bool {
+	switch name {
+	case "php", "php-64bit", "php-ipv6", "php-zts", "php-debug", "hhvm",
+		"composer", "composer-plugin-api", "composer-runtime-api":
+		return true
+	}
+	return strings.HasPrefix(name, "ext-") || strings.HasPrefix(name, "lib-")
+}
+
+// isTrackedPackage reports whether a required package takes part in the
+// analysis. Regular packages do unless they are external and match
+// IgnoredPrefixes; platform packages only with IncludePlatform.
+func (pa *PluginAnalyzer) isTrackedPackage(name string) bool {
+	if pa.isIgnoredExternal(name) {
+		return false
+	}
+	if isPlatformPackage(name) {
+		return pa.IncludePlatform
+	}
+	return strings.Contains(name, "/")
+}
+
+// isIgnoredExternal reports whether name matches one of IgnoredPrefixes and
+// is not a scanned plugin.
+func (pa *PluginAnalyzer) isIgnoredExternal(name string) bool {
+	if plugin, ok := pa.Plugins[name]; ok && !plugin.IsExternal {
+		return false
+	}
+	for _, prefix := range pa.IgnoredPrefixes {
+		if strings.HasPrefix(name, normalizePackageName(prefix)) {
+			return true
+		}
+	}
+	return false
+}
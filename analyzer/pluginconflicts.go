@@ -0,0 +1,87 @@
+package analyzer
+
+import "sort"
+
+// PluginConflict is a conflict entry of one internal plugin that matches
+// another scanned plugin, so the two cannot be installed together.
+type PluginConflict struct {
+	Plugin     string // folder of the plugin declaring the conflict
+	Target     string // folder of the conflicting plugin
+	Constraint string
+	Version    string // version declared by the target, may be empty
+}
+
+// conflictApplies reports whether a conflict constraint matches the target
+// plugin's version. A target without a parsable version, or a constraint
+// that cannot be parsed, is assumed to conflict.
+func conflictApplies(constraint string, target *Plugin) bool {
+	v, _, err := parseVersion(target.Version)
+	if err != nil {
+		return true
+	}
+	c, err := parseConstraint(constraint)
+	if err != nil {
+		return true
+	}
+	return c.allows(v)
+}
+
+// PluginConflicts returns the conflict entries between internal plugins
+// that apply to the scanned versions, sorted by declaring plugin and target.
+func (pa *PluginAnalyzer) PluginConflicts() []PluginConflict {
+	var conflicts []PluginConflict
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		for pkg, constraint := range plugin.Conflict {
+			target, ok := pa.Plugins[pa.canonicalName(pkg)]
+			if !ok || target.IsExternal || target == plugin || !conflictApplies(constraint, target) {
+				continue
+			}
+			conflicts = append(conflicts, PluginConflict{
+				Plugin:     plugin.FolderName,
+				Target:     target.FolderName,
+				Constraint: constraint,
+				Version:    target.Version,
+			})
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Plugin != conflicts[j].Plugin {
+			return conflicts[i].Plugin < conflicts[j].Plugin
+		}
+		return conflicts[i].Target < conflicts[j].Target
+	})
+	return conflicts
+}
+
+// conflictEdge is a conflict entry drawn with ShowConflict.
+type conflictEdge struct {
+	From, To   string // composer names
+	Constraint string
+}
+
+// conflictEdges returns the conflict entries of rendered plugins whose
+// target is a node of the graph, sorted by plugin and target.
+func (pa *PluginAnalyzer) conflictEdges() []conflictEdge {
+	var edges []conflictEdge
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal {
+			continue
+		}
+		var targets []string
+		for pkg := range plugin.Conflict {
+			targets = append(targets, pkg)
+		}
+		sort.Strings(targets)
+		for _, pkg := range targets {
+			to := pa.canonicalName(pkg)
+			target, ok := pa.Plugins[to]
+			if !ok || target == plugin || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+			edges = append(edges, conflictEdge{From: plugin.Name, To: to, Constraint: plugin.Conflict[pkg]})
+		}
+	}
+	return edges
+}
@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoPlugins is returned by ScanPlugins when none of the plugins
+// directories contains a plugin folder, which usually means -dir points
+// to the wrong place.
+var ErrNoPlugins = errors.New("no plugins found")
+
+// PluginsDirError is a plugins directory that cannot be scanned because it
+// does not exist or is not a directory.
+type PluginsDirError struct {
+	Dir    string // as given, e.g. on the command line
+	Path   string // absolute path of Dir
+	Reason string
+}
+
+func (e *PluginsDirError) Error() string {
+	if e.Path == e.Dir {
+		return fmt.Sprintf("plugins directory %q %s", e.Dir, e.Reason)
+	}
+	return fmt.Sprintf("plugins directory %q (%s) %s", e.Dir, e.Path, e.Reason)
+}
+
+// validatePluginsDir returns a *PluginsDirError if dir does not exist or is
+// not a directory.
+func validatePluginsDir(dir string) error {
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		path = dir
+	}
+	info, err := os.Stat(dir)
+	switch {
+	case os.IsNotExist(err):
+		return &PluginsDirError{Dir: dir, Path: path, Reason: "does not exist"}
+	case err != nil:
+		return fmt.Errorf("failed to read plugins directory %s: %w", path, err)
+	case !info.IsDir():
+		return &PluginsDirError{Dir: dir, Path: path, Reason: "is not a directory"}
+	}
+	return nil
+}
+
+// noPluginsError describes the plugins directories that held no plugin
+// folders, by absolute path.
+func noPluginsError(dirs []string) error {
+	paths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		if path, err := filepath.Abs(dir); err == nil {
+			dir = path
+		}
+		paths[i] = dir
+	}
+	return fmt.Errorf("%w in %s", ErrNoPlugins, strings.Join(paths, ", "))
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// pluginXMLFile is the optional Shopware plugin descriptor next to a
+// plugin's composer.json, carrying its human-friendly label.
+const pluginXMLFile = "plugin.xml"
+
+// pluginXML is the part of a plugin.xml the analyzer reads.
+type pluginXML struct {
+	Labels []struct {
+		Lang string `xml:"lang,attr"`
+		Text string `xml:",chardata"`
+	} `xml:"label"`
+}
+
+// preferredLabelLanguages are tried in order when a plugin has labels in
+// several languages; otherwise the alphabetically first language wins.
+var preferredLabelLanguages = []string{"en", "en-GB", "en-US"}
+
+// pickLabel returns the label in the preferred language from labels keyed
+// by language, or "" if there are none.
+func pickLabel(labels map[string]string) string {
+	for _, lang := range preferredLabelLanguages {
+		if label := strings.TrimSpace(labels[lang]); label != "" {
+			return label
+		}
+	}
+	langs := make([]string, 0, len(labels))
+	for lang, label := range labels {
+		if strings.TrimSpace(label) != "" {
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) == 0 {
+		return ""
+	}
+	sort.Strings(langs)
+	return strings.TrimSpace(labels[langs[0]])
+}
+
+// loadPluginLabel reads the label of the plugin in folder, a slash-separated
+// path within fsys, from its plugin.xml. A missing plugin.xml is not an error and yields "".
+func loadPluginLabel(fsys fs.FS, folder string) (string, error) {
+	data, err := fs.ReadFile(fsys, path.Join(folder, pluginXMLFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var descriptor pluginXML
+	if err := xml.Unmarshal(data, &descriptor); err != nil {
+		return "", fmt.Errorf("invalid %s: %w", pluginXMLFile, err)
+	}
+	labels := make(map[string]string)
+	for _, label := range descriptor.Labels {
+		if _, ok := labels[label.Lang]; !ok {
+			labels[label.Lang] = label.Text
+		}
+	}
+	return pickLabel(labels), nil
+}
+
+// composerLabel returns the label from a composer.json's extra.label, which
+// Shopware 6 plugins give per locale, e.g. {"en-GB": "My Plugin"}. A plain
+// string is accepted too; anything else yields "".
+func composerLabel(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var labels map[string]string
+	if err := json.Unmarshal(raw, &labels); err == nil {
+		return pickLabel(labels)
+	}
+	var label string
+	if err := json.Unmarshal(raw, &label); err == nil {
+		return strings.TrimSpace(label)
+	}
+	return ""
+}
+
+// displayName returns the caption of a node: the plugin's label if it has
+// one, its folder name otherwise.
+func (p *Plugin) displayName() string {
+	if p.Label != "" {
+		return p.Label
+	}
+	return p.FolderName
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoKinds maps dependency kinds to the DependencyKind enum values of
+// proto/plugin_graph.proto.
+var protoKinds = map[DependencyKind]uint64{
+	KindRequire:    0,
+	KindRequireDev: 1,
+	KindSuggest:    2,
+	KindBundle:     3,
+}
+
+// GenerateProtobuf serializes the graph as a PluginGraph message as defined
+// in proto/plugin_graph.proto. Nodes and edges are sorted by name so the
+// output is stable.
+func (pa *PluginAnalyzer) GenerateProtobuf() []byte {
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var graph []byte
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+
+		var node []byte
+		node = appendProtoString(node, 1, plugin.Name)
+		node = appendProtoString(node, 2, plugin.FolderName)
+		if plugin.IsExternal {
+			node = protowire.AppendTag(node, 3, protowire.VarintType)
+			node = protowire.AppendVarint(node, 1)
+		}
+		node = appendProtoString(node, 4, plugin.Version)
+
+		graph = protowire.AppendTag(graph, 1, protowire.BytesType)
+		graph = protowire.AppendBytes(graph, node)
+	}
+
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		for _, dep := range plugin.Dependencies {
+			if target, ok := pa.Plugins[dep.Name]; !ok || (target.IsExternal && !pa.ShowExternalDeps) {
+				continue
+			}
+
+			var edge []byte
+			edge = appendProtoString(edge, 1, plugin.Name)
+			edge = appendProtoString(edge, 2, dep.Name)
+			if kind := protoKinds[dep.Kind]; kind != 0 {
+				edge = protowire.AppendTag(edge, 3, protowire.VarintType)
+				edge = protowire.AppendVarint(edge, kind)
+			}
+			if dep.Optional {
+				edge = protowire.AppendTag(edge, 4, protowire.VarintType)
+				edge = protowire.AppendVarint(edge, 1)
+			}
+
+			graph = protowire.AppendTag(graph, 2, protowire.BytesType)
+			graph = protowire.AppendBytes(graph, edge)
+		}
+	}
+
+	return graph
+}
+
+// appendProtoString appends a string field, omitting it when empty as proto3
+// does for default values.
+func appendProtoString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ExposedPlugin is an internal plugin together with the number of hops to
+// the nearest external dependency in the graph.
+type ExposedPlugin struct {
+	Name       string
+	FolderName string
+	Hops       int
+}
+
+// ExternalProximity returns the internal plugins that reach an external node
+// of the graph, with their distance in hops: 1 for plugins requiring an
+// external package directly, 2 for plugins depending on those, and so on.
+// Only external nodes present in the graph count, so this needs
+// ShowExternalDeps. Suggest edges are not followed. The result is sorted by
+// hops and then by folder name.
+func (pa *PluginAnalyzer) ExternalProximity() []ExposedPlugin {
+	hops := make(map[string]int)
+	var queue []string
+	for _, name := range pa.InternalPluginNames() {
+		for _, dep := range pa.Plugins[name].Dependencies {
+			if dep.Kind != KindSuggest && pa.Plugins[dep.Name].IsExternal {
+				hops[name] = 1
+				queue = append(queue, name)
+				break
+			}
+		}
+	}
+
+	// Walk the internal edges backwards, one hop per level.
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range pa.directDependents(current) {
+			if _, seen := hops[dependent]; seen {
+				continue
+			}
+			hops[dependent] = hops[current] + 1
+			queue = append(queue, dependent)
+		}
+	}
+
+	exposed := make([]ExposedPlugin, 0, len(hops))
+	for name, h := range hops {
+		exposed = append(exposed, ExposedPlugin{Name: name, FolderName: pa.Plugins[name].FolderName, Hops: h})
+	}
+	sort.Slice(exposed, func(i, j int) bool {
+		if exposed[i].Hops != exposed[j].Hops {
+			return exposed[i].Hops < exposed[j].Hops
+		}
+		return exposed[i].FolderName < exposed[j].FolderName
+	})
+	return exposed
+}
+
+// ProximityScope returns the subgraph of internal plugins within maxHops of
+// an external dependency, together with the external packages they require
+// directly.
+func (pa *PluginAnalyzer) ProximityScope(maxHops int) (*PluginAnalyzer, error) {
+	keep := make(map[string]bool)
+	for _, p := range pa.ExternalProximity() {
+		if p.Hops > maxHops {
+			continue
+		}
+		keep[p.Name] = true
+		for _, dep := range pa.Plugins[p.Name].Dependencies {
+			if pa.Plugins[dep.Name].IsExternal {
+				keep[dep.Name] = true
+			}
+		}
+	}
+
+	if len(keep) == 0 {
+		return nil, fmt.Errorf("no internal plugins within %d hop(s) of an external dependency", maxHops)
+	}
+	return pa.subset(keep), nil
+}
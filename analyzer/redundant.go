@@ -0,0 +1,70 @@
+package analyzer
+
+import "sort"
+
+// RedundantDependency is a direct internal dependency a plugin also gets
+// through another of its direct dependencies.
+type RedundantDependency struct {
+	Plugin     string // folder of the requiring plugin
+	Dependency string // folder of the redundant requirement
+	Via        string // folder of the direct dependency already requiring it
+}
+
+// reachableFrom returns the internal plugins reachable from start along
+// internal dependencies without passing through avoid. Each plugin is
+// visited once, so cycles terminate.
+func (pa *PluginAnalyzer) reachableFrom(start, avoid string) map[string]bool {
+	reached := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dep := range pa.internalDependencies(pa.Plugins[current]) {
+			if dep != avoid && !reached[dep] {
+				reached[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return reached
+}
+
+// RedundantDependencies returns the direct internal dependencies that are
+// reachable through another direct dependency of the same plugin, sorted by
+// plugin and dependency folder. Paths leading back through the plugin
+// itself don't count. Two dependencies in a cycle with each other are not
+// reported, since dropping either requirement would be fine but dropping
+// both would not.
+func (pa *PluginAnalyzer) RedundantDependencies() []RedundantDependency {
+	var redundant []RedundantDependency
+	for _, name := range pa.InternalPluginNames() {
+		deps := pa.internalDependencies(pa.Plugins[name])
+		if len(deps) < 2 {
+			continue
+		}
+		reach := make(map[string]map[string]bool, len(deps))
+		for _, dep := range deps {
+			reach[dep] = pa.reachableFrom(dep, name)
+		}
+		for _, dep := range deps {
+			for _, via := range deps {
+				if via != dep && reach[via][dep] && !reach[dep][via] {
+					redundant = append(redundant, RedundantDependency{
+						Plugin:     pa.Plugins[name].FolderName,
+						Dependency: pa.Plugins[dep].FolderName,
+						Via:        pa.Plugins[via].FolderName,
+					})
+					break
+				}
+			}
+		}
+	}
+
+	sort.Slice(redundant, func(i, j int) bool {
+		if redundant[i].Plugin != redundant[j].Plugin {
+			return redundant[i].Plugin < redundant[j].Plugin
+		}
+		return redundant[i].Dependency < redundant[j].Dependency
+	})
+	return redundant
+}
@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// RootOverrides are the project-wide conflict and replace sections of the
+// root composer.json of a Shopware project, and the packages it requires.
+type RootOverrides struct {
+	Conflict map[string]string `json:"conflict"` // package -> versions that may not be installed
+	Replace  map[string]string `json:"replace"`  // package -> version the root provides instead
+	Require  map[string]string `json:"require"`  // packages the project installs, see ProjectScope
+}
+
+// LoadRootComposer reads the conflict, replace and require sections of a
+// project's root composer.json. Package names are normalized like require
+// keys.
+func LoadRootComposer(path string) (*RootOverrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err = ToUTF8(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var root RootOverrides
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	root.Conflict = normalizeRequirements(root.Conflict)
+	root.Replace = normalizeRequirements(root.Replace)
+	root.Require = normalizeRequirements(root.Require)
+	return &root, nil
+}
+
+// ProjectScope returns the subgraph a project actually uses: the scanned
+// plugins its root composer.json requires, marked as Entry, with everything
+// they need as RootScope keeps it. Required packages that aren't scanned
+// plugins, such as shopware/core, are skipped.
+func (pa *PluginAnalyzer) ProjectScope(project *RootOverrides) (*PluginAnalyzer, error) {
+	var entries []string
+	for pkg := range project.Require {
+		if plugin, ok := pa.Plugins[pa.canonicalName(pkg)]; ok && !plugin.IsExternal {
+			entries = append(entries, plugin.Name)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("the project requires none of the scanned plugins")
+	}
+
+	scoped := pa.shipScope(entries)
+	for _, name := range entries {
+		scoped.Plugins[name].Entry = true
+	}
+	return scoped, nil
+}
+
+// replacedByRoot reports whether the root composer.json replaces the named
+// package, so requiring it needs no separate install.
+func (pa *PluginAnalyzer) replacedByRoot(name string) bool {
+	if pa.Root == nil {
+		return false
+	}
+	_, ok := pa.Root.Replace[name]
+	return ok
+}
+
+// rootAllowed returns the versions of the named package the root
+// composer.json doesn't conflict with, and the conflict constraint. ok is
+// false if there is no parsable conflict entry for the package.
+func (pa *PluginAnalyzer) rootAllowed(name string) (allowed constraint, conflict string, ok bool) {
+	if pa.Root == nil {
+		return nil, "", false
+	}
+	conflict, ok = pa.Root.Conflict[name]
+	if !ok {
+		return nil, "", false
+	}
+	c, err := parseConstraint(conflict)
+	if err != nil {
+		return nil, "", false
+	}
+	return c.complement(), conflict, true
+}
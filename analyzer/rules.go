@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DependencyRule forbids edges from plugins matching From to plugins
+// matching To. Patterns are globs as understood by path.Match.
+type DependencyRule struct {
+	From, To string
+	Line     int // line in the rules file, for error messages
+}
+
+func (r DependencyRule) String() string {
+	return fmt.Sprintf("deny: %q -> %q", r.From, r.To)
+}
+
+// RuleViolation is an edge forbidden by a dependency rule.
+type RuleViolation struct {
+	From, To string // folder names
+	Kind     DependencyKind
+	Rule     DependencyRule
+}
+
+// LoadRules reads a rules file with one rule per line:
+//
+//	deny: "*-core" -> "*-ui"
+//
+// Quotes are optional. Blank lines and lines starting with "#" are ignored.
+func LoadRules(filename string) ([]DependencyRule, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	defer file.Close()
+
+	var rules []DependencyRule
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		rule, err := parseRule(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+		rule.Line = line
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	return rules, nil
+}
+
+func parseRule(text string) (DependencyRule, error) {
+	action, body, ok := strings.Cut(text, ":")
+	if !ok || strings.TrimSpace(action) != "deny" {
+		return DependencyRule{}, fmt.Errorf("expected `deny: \"from\" -> \"to\"`, got %q", text)
+	}
+	from, to, ok := strings.Cut(body, "->")
+	if !ok {
+		return DependencyRule{}, fmt.Errorf("missing -> in %q", text)
+	}
+
+	var patterns [2]string
+	for i, raw := range []string{from, to} {
+		p := strings.TrimSpace(raw)
+		if unquoted, err := strconv.Unquote(p); err == nil {
+			p = unquoted
+		}
+		if _, err := path.Match(p, ""); err != nil || p == "" {
+			return DependencyRule{}, fmt.Errorf("invalid pattern %q", p)
+		}
+		patterns[i] = p
+	}
+	return DependencyRule{From: patterns[0], To: patterns[1]}, nil
+}
+
+// matchesPlugin reports whether a rule pattern matches the plugin's composer
+// name, the package part of it without the vendor, or its folder name.
+func matchesPlugin(pattern string, plugin *Plugin) bool {
+	candidates := []string{plugin.Name, plugin.FolderName}
+	if _, pkg, ok := strings.Cut(plugin.Name, "/"); ok {
+		candidates = append(candidates, pkg)
+	}
+	for _, c := range candidates {
+		if ok, _ := path.Match(pattern, c); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleViolations returns every edge of the graph forbidden by one of the
+// Analyzer's Rules, sorted by source and target.
+func (pa *PluginAnalyzer) RuleViolations() []RuleViolation {
+	var violations []RuleViolation
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		for _, dep := range plugin.Dependencies {
+			target := pa.Plugins[dep.Name]
+			for _, rule := range pa.Rules {
+				if matchesPlugin(rule.From, plugin) && matchesPlugin(rule.To, target) {
+					violations = append(violations, RuleViolation{From: plugin.FolderName, To: target.FolderName, Kind: dep.Kind, Rule: rule})
+					break
+				}
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].From != violations[j].From {
+			return violations[i].From < violations[j].From
+		}
+		return violations[i].To < violations[j].To
+	})
+	return violations
+}
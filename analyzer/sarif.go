@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"path/filepath"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifRules describes every rule a Finding may reference.
+var sarifRules = []sarifRule{
+	{ID: RuleCircularDependency, ShortDescription: sarifMessage{Text: "Plugins depend on each other in a cycle"}},
+	{ID: RuleVersionConflict, ShortDescription: sarifMessage{Text: "Plugins require a package with incompatible version constraints"}},
+	{ID: RuleMissingInternal, ShortDescription: sarifMessage{Text: "An internal plugin is required but was not found"}},
+	{ID: RuleRedundantDev, ShortDescription: sarifMessage{Text: "A package is listed in both require and require-dev"}},
+	{ID: RuleRedundantDirect, ShortDescription: sarifMessage{Text: "A direct internal dependency is already pulled in through another one"}},
+	{ID: RulePossibleTypo, ShortDescription: sarifMessage{Text: "An external requirement is close to the name of an internal plugin"}},
+	{ID: RuleDeniedPackage, ShortDescription: sarifMessage{Text: "A plugin requires a package on the denylist"}},
+	{ID: RuleForbiddenEdge, ShortDescription: sarifMessage{Text: "A dependency violates an architecture rule"}},
+	{ID: RuleVersionMismatch, ShortDescription: sarifMessage{Text: "An internal dependency's constraint excludes the major version of the required plugin"}},
+	{ID: RuleManifestDeviation, ShortDescription: sarifMessage{Text: "The dependencies differ from the architecture manifest"}},
+	{ID: RuleDeprecatedTarget, ShortDescription: sarifMessage{Text: "A plugin depends on a deprecated plugin"}},
+	{ID: RuleDuplicateClass, ShortDescription: sarifMessage{Text: "Several plugins declare the same shopware-plugin-class"}},
+	{ID: RuleDuplicateName, ShortDescription: sarifMessage{Text: "Several plugin folders declare the same composer name"}},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// GenerateSARIF reports the analyzer's findings as a SARIF 2.1.0 log, with
+// each result located at the composer.json of the plugin concerned.
+func (pa *PluginAnalyzer) GenerateSARIF() ([]byte, error) {
+	results := []sarifResult{}
+	for _, f := range pa.Findings() {
+		result := sarifResult{
+			RuleID:  f.RuleID,
+			Level:   f.Level,
+			Message: sarifMessage{Text: f.Message},
+		}
+		if f.Plugin != nil {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(pa.composerPath(f.Plugin))},
+				},
+			}}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "sw6-plugin-analyzer",
+				InformationURI: "https://github.com/topdata-software-gmbh/sw6-plugin-analyzer",
+				Rules:          sarifRules,
+			}},
+			Results: results,
+		}},
+	}
+
+	return json.MarshalIndent(log, "", "  ")
+}
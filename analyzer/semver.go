@@ -0,0 +1,293 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// version is a simplified semantic version used for constraint checks.
+// Pre-release and build suffixes are ignored.
+type version [3]int
+
+func (v version) compare(o version) int {
+	for i := range v {
+		if v[i] != o[i] {
+			if v[i] < o[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// parseVersion parses a composer version such as "v1.2", "1.2.3" or
+// "1.2.3-beta1". It returns the version and the number of numeric parts
+// that were given explicitly.
+func parseVersion(s string) (version, int, error) {
+	var v version
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	if i := strings.IndexAny(s, "-+@"); i >= 0 {
+		s = s[:i]
+	}
+	if s == "" {
+		return v, 0, fmt.Errorf("empty version")
+	}
+
+	parts := strings.Split(s, ".")
+	n := 0
+	for i, part := range parts {
+		if i >= len(v) {
+			break // composer allows a fourth component; it doesn't matter here
+		}
+		if part == "*" || part == "x" || part == "X" {
+			break
+		}
+		num, err := strconv.Atoi(part)
+		if err != nil {
+			return v, 0, fmt.Errorf("invalid version %q", s)
+		}
+		v[i] = num
+		n++
+	}
+	if n == 0 {
+		return v, 0, fmt.Errorf("invalid version %q", s)
+	}
+	return v, n, nil
+}
+
+// bump returns the smallest version above every version sharing the first
+// n parts of v, e.g. bump(1.2.3, 2) == 1.3.0.
+func (v version) bump(n int) version {
+	var next version
+	copy(next[:n], v[:n])
+	next[n-1]++
+	return next
+}
+
+// versionRange is a contiguous interval of versions. A missing bound is
+// unbounded.
+type versionRange struct {
+	min, max         version
+	hasMin, hasMax   bool
+	minIncl, maxIncl bool
+}
+
+func (r versionRange) contains(v version) bool {
+	if r.hasMin {
+		c := v.compare(r.min)
+		if c < 0 || (c == 0 && !r.minIncl) {
+			return false
+		}
+	}
+	if r.hasMax {
+		c := v.compare(r.max)
+		if c > 0 || (c == 0 && !r.maxIncl) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r versionRange) empty() bool {
+	if !r.hasMin || !r.hasMax {
+		return false
+	}
+	c := r.min.compare(r.max)
+	return c > 0 || (c == 0 && !(r.minIncl && r.maxIncl))
+}
+
+// intersect returns the overlap of two ranges, which may be empty.
+func (r versionRange) intersect(o versionRange) versionRange {
+	out := r
+	if o.hasMin {
+		if !out.hasMin || o.min.compare(out.min) > 0 || (o.min.compare(out.min) == 0 && !o.minIncl) {
+			out.min, out.minIncl, out.hasMin = o.min, o.minIncl, true
+		}
+	}
+	if o.hasMax {
+		if !out.hasMax || o.max.compare(out.max) < 0 || (o.max.compare(out.max) == 0 && !o.maxIncl) {
+			out.max, out.maxIncl, out.hasMax = o.max, o.maxIncl, true
+		}
+	}
+	return out
+}
+
+// constraint is a composer version constraint in disjunctive form: a version
+// satisfies it if any of its ranges contains it.
+type constraint []versionRange
+
+// anyVersion is the constraint matching every version.
+var anyVersion = constraint{{}}
+
+func (c constraint) allows(v version) bool {
+	for _, r := range c {
+		if r.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// intersect returns the constraint satisfied by versions matching both c and o.
+func (c constraint) intersect(o constraint) constraint {
+	var out constraint
+	for _, a := range c {
+		for _, b := range o {
+			if r := a.intersect(b); !r.empty() {
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+// complement returns the constraint satisfied by exactly the versions c
+// doesn't allow.
+func (c constraint) complement() constraint {
+	out := anyVersion
+	for _, r := range c {
+		var outside constraint
+		if r.hasMin {
+			outside = append(outside, versionRange{max: r.min, hasMax: true, maxIncl: !r.minIncl})
+		}
+		if r.hasMax {
+			outside = append(outside, versionRange{min: r.max, hasMin: true, minIncl: !r.maxIncl})
+		}
+		out = out.intersect(outside)
+	}
+	return out
+}
+
+// satisfiable reports whether any version satisfies the constraint.
+func (c constraint) satisfiable() bool {
+	return len(c) > 0
+}
+
+// parseConstraint parses a composer version constraint such as "^1.2",
+// "~6.5.0", ">=1.0 <2.0", "1.0.*", "1.0 - 2.0" or "^1.0 || ^2.0".
+// Branch constraints like "dev-master" match any version.
+func parseConstraint(s string) (constraint, error) {
+	var out constraint
+	for _, alt := range strings.Split(strings.ReplaceAll(s, "||", "|"), "|") {
+		r, err := parseConjunction(strings.TrimSpace(alt))
+		if err != nil {
+			return nil, err
+		}
+		if !r.empty() {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// parseConjunction parses the AND-ed terms of one constraint alternative.
+func parseConjunction(s string) (versionRange, error) {
+	if lo, hi, ok := strings.Cut(s, " - "); ok {
+		return parseHyphenRange(strings.TrimSpace(lo), strings.TrimSpace(hi))
+	}
+
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	var terms []string
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+		// Join operators separated from their version, e.g. ">= 1.0".
+		if strings.Trim(term, "<>=!^~") == "" && i+1 < len(fields) {
+			term += fields[i+1]
+			i++
+		}
+		terms = append(terms, term)
+	}
+
+	result := versionRange{}
+	for _, term := range terms {
+		r, err := parseTerm(term)
+		if err != nil {
+			return result, err
+		}
+		result = result.intersect(r)
+	}
+	return result, nil
+}
+
+func parseHyphenRange(lo, hi string) (versionRange, error) {
+	min, _, err := parseVersion(lo)
+	if err != nil {
+		return versionRange{}, err
+	}
+	max, n, err := parseVersion(hi)
+	if err != nil {
+		return versionRange{}, err
+	}
+	r := versionRange{min: min, hasMin: true, minIncl: true, hasMax: true}
+	if n == len(max) {
+		r.max, r.maxIncl = max, true
+	} else {
+		r.max = max.bump(n)
+	}
+	return r, nil
+}
+
+// parseTerm parses a single constraint term like "^1.2" or ">=2.0".
+func parseTerm(term string) (versionRange, error) {
+	if i := strings.Index(term, "@"); i >= 0 {
+		term = term[:i] // stability flag, e.g. "^1.0@beta"
+	}
+	if term == "" || term == "*" || strings.HasPrefix(term, "dev-") {
+		return versionRange{}, nil
+	}
+
+	switch {
+	case strings.HasPrefix(term, "^"):
+		v, n, err := parseVersion(term[1:])
+		if err != nil {
+			return versionRange{}, err
+		}
+		// The first non-zero part is the one that may not change.
+		keep := 1
+		for keep < n && v[keep-1] == 0 {
+			keep++
+		}
+		return versionRange{min: v, hasMin: true, minIncl: true, max: v.bump(keep), hasMax: true}, nil
+	case strings.HasPrefix(term, "~"):
+		v, n, err := parseVersion(term[1:])
+		if err != nil {
+			return versionRange{}, err
+		}
+		keep := n - 1
+		if keep < 1 {
+			keep = 1
+		}
+		return versionRange{min: v, hasMin: true, minIncl: true, max: v.bump(keep), hasMax: true}, nil
+	case strings.HasPrefix(term, ">="):
+		v, _, err := parseVersion(term[2:])
+		return versionRange{min: v, hasMin: true, minIncl: true}, err
+	case strings.HasPrefix(term, "<="):
+		v, _, err := parseVersion(term[2:])
+		return versionRange{max: v, hasMax: true, maxIncl: true}, err
+	case strings.HasPrefix(term, "!="):
+		return versionRange{}, nil // exclusions of a single version are not modeled
+	case strings.HasPrefix(term, ">"):
+		v, _, err := parseVersion(term[1:])
+		return versionRange{min: v, hasMin: true}, err
+	case strings.HasPrefix(term, "<"):
+		v, _, err := parseVersion(term[1:])
+		return versionRange{max: v, hasMax: true}, err
+	}
+
+	term = strings.TrimLeft(term, "=")
+	v, n, err := parseVersion(term)
+	if err != nil {
+		return versionRange{}, err
+	}
+	if strings.ContainsAny(term, "*xX") {
+		// Wildcard versions such as "1.2.*".
+		return versionRange{min: v, hasMin: true, minIncl: true, max: v.bump(n), hasMax: true}, nil
+	}
+	return versionRange{min: v, hasMin: true, minIncl: true, max: v, hasMax: true, maxIncl: true}, nil
+}
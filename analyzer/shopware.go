@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+)
+
+// shopwareCorePackage is the package whose constraint tells which Shopware
+// Versions a plugin supports.
+const shopwareCorePackage = "shopware/core"
+
+// unknownShopwareVersion is the ShopwareVersion of plugins that don't
+// require shopware/core.
+const unknownShopwareVersion = "unknown"
+
+// shopwareVersion returns the shopware/core constraint of a require section,
+// or unknownShopwareVersion if there is none.
+func shopwareVersion(require map[string]string) string {
+	if constraint, ok := require[shopwareCorePackage]; ok {
+		return constraint
+	}
+	return unknownShopwareVersion
+}
+
+// ShopwareBlocker is a plugin whose shopware/core constraint excludes a
+// target Shopware version.
+type ShopwareBlocker struct {
+	Plugin     string // folder name
+	Constraint string
+}
+
+// ShopwareBlockers returns the internal plugins whose shopware/core
+// constraint excludes the given Shopware version, sorted by folder name.
+// Plugins with an unknown or unparsable constraint are not reported.
+func (pa *PluginAnalyzer) ShopwareBlockers(target string) ([]ShopwareBlocker, error) {
+	v, _, err := parseVersion(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Shopware version %q: %w", target, err)
+	}
+
+	var blockers []ShopwareBlocker
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		if plugin.ShopwareVersion == unknownShopwareVersion {
+			continue
+		}
+		c, err := parseConstraint(plugin.ShopwareVersion)
+		if err != nil || c.allows(v) {
+			continue
+		}
+		blockers = append(blockers, ShopwareBlocker{Plugin: plugin.FolderName, Constraint: plugin.ShopwareVersion})
+	}
+	sort.Slice(blockers, func(i, j int) bool { return blockers[i].Plugin < blockers[j].Plugin })
+	return blockers, nil
+}
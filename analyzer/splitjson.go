@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PluginDetail is the per-plugin document written by -split-json.
+type PluginDetail struct {
+	Name         string             `json:"name"`
+	FolderName   string             `json:"folderName"`
+	Version      string             `json:"version,omitempty"`
+	Dependencies []dependencyDetail `json:"dependencies"`
+	Dependents   []string           `json:"dependents"`
+	Metrics      metricsDetail      `json:"metrics"`
+}
+
+type dependencyDetail struct {
+	Name       string         `json:"name"`
+	Kind       DependencyKind `json:"kind"`
+	Constraint string         `json:"constraint,omitempty"`
+	IsExternal bool           `json:"isExternal"`
+	Optional   bool           `json:"optional"`
+}
+
+type metricsDetail struct {
+	FanIn  int `json:"fanIn"`
+	FanOut int `json:"fanOut"`
+}
+
+// directDependents returns the sorted composer names of the internal
+// plugins that depend on the plugin called name.
+func (pa *PluginAnalyzer) directDependents(name string) []string {
+	var dependents []string
+	for _, candidate := range pa.InternalPluginNames() {
+		for _, dep := range pa.internalDependencies(pa.Plugins[candidate]) {
+			if dep == name {
+				dependents = append(dependents, candidate)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// PluginDetail builds the JSON document describing one internal plugin.
+func (pa *PluginAnalyzer) PluginDetail(name string, metrics map[string]PluginMetrics) PluginDetail {
+	plugin := pa.Plugins[name]
+	detail := PluginDetail{
+		Name:         plugin.Name,
+		FolderName:   plugin.FolderName,
+		Version:      plugin.Version,
+		Dependencies: []dependencyDetail{},
+		Dependents:   []string{},
+		Metrics:      metricsDetail{FanIn: metrics[name].FanIn, FanOut: metrics[name].FanOut},
+	}
+
+	for _, dep := range plugin.Dependencies {
+		target, ok := pa.Plugins[dep.Name]
+		detail.Dependencies = append(detail.Dependencies, dependencyDetail{
+			Name:       dep.Name,
+			Kind:       dep.Kind,
+			Constraint: dep.Constraint,
+			IsExternal: !ok || target.IsExternal,
+			Optional:   dep.Optional,
+		})
+	}
+	sort.Slice(detail.Dependencies, func(i, j int) bool {
+		a, b := detail.Dependencies[i], detail.Dependencies[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return kindOrder[a.Kind] < kindOrder[b.Kind]
+	})
+
+	for _, dependent := range pa.directDependents(name) {
+		detail.Dependents = append(detail.Dependents, pa.Plugins[dependent].FolderName)
+	}
+	return detail
+}
+
+// WriteSplitJSON writes one JSON document per internal plugin to
+// dir/<folder>.json and returns the number of files written.
+func (pa *PluginAnalyzer) WriteSplitJSON(dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	metrics := pa.Metrics()
+	written := 0
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		detail := pa.PluginDetail(name, metrics)
+
+		data, err := json.MarshalIndent(detail, "", "  ")
+		if err != nil {
+			return written, fmt.Errorf("failed to encode %s: %w", plugin.FolderName, err)
+		}
+		file := filepath.Join(dir, strings.ReplaceAll(plugin.FolderName, "/", "_")+".json")
+		if err := os.WriteFile(file, append(data, '\n'), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		written++
+	}
+
+	return written, nil
+}
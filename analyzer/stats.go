@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GraphStats is a one-glance summary of the size and complexity of the
+// dependency graph.
+type GraphStats struct {
+	InternalPlugins       int    `json:"internalPlugins"`
+	ExternalDependencies  int    `json:"externalDependencies"`
+	Edges                 int    `json:"edges"`
+	Cycles                int    `json:"cycles"`
+	MaxDepth              int    `json:"maxDepth"`
+	MostDependedUpon      string `json:"mostDependedUpon,omitempty"` // folder name
+	MostDependedUponFanIn int    `json:"mostDependedUponFanIn"`
+}
+
+// Stats computes the graph statistics. Edges counts the distinct pairs of
+// a plugin and a package it depends on, including external packages only
+// with -show-external. MostDependedUpon is the internal plugin with the
+// highest fan-in, the first by folder name on a tie, and empty if no plugin
+// has dependents.
+func (pa *PluginAnalyzer) Stats() GraphStats {
+	stats := GraphStats{
+		InternalPlugins:      len(pa.InternalPluginNames()),
+		ExternalDependencies: len(pa.ExternalDepsCount),
+		Cycles:               len(pa.DetectCycles()),
+	}
+
+	for _, name := range pa.InternalPluginNames() {
+		targets := make(map[string]bool)
+		for _, dep := range pa.Plugins[name].Dependencies {
+			targets[dep.Name] = true
+		}
+		stats.Edges += len(targets)
+	}
+
+	for _, depth := range pa.Depths() {
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+	}
+
+	metrics := pa.Metrics()
+	for _, name := range pa.InternalPluginNames() {
+		m := metrics[name]
+		if m.FanIn > stats.MostDependedUponFanIn ||
+			(m.FanIn == stats.MostDependedUponFanIn && m.FanIn > 0 && m.FolderName < stats.MostDependedUpon) {
+			stats.MostDependedUpon, stats.MostDependedUponFanIn = m.FolderName, m.FanIn
+		}
+	}
+	return stats
+}
+
+// StatsBudget holds upper limits for GraphStats, so CI can stop the graph
+// from growing. A negative limit is not checked.
+type StatsBudget struct {
+	MaxEdges  int
+	MaxCycles int
+	MaxDepth  int
+}
+
+// Exceeded returns one line per limit of the budget stats exceed, naming the
+// metric, its value and the limit, e.g. "edges: 95 > 90".
+func (b StatsBudget) Exceeded(stats GraphStats) []string {
+	var exceeded []string
+	for _, check := range []struct {
+		metric       string
+		value, limit int
+	}{
+		{"edges", stats.Edges, b.MaxEdges},
+		{"cycles", stats.Cycles, b.MaxCycles},
+		{"max depth", stats.MaxDepth, b.MaxDepth},
+	} {
+		if check.limit >= 0 && check.value > check.limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s: %d > %d", check.metric, check.value, check.limit))
+		}
+	}
+	return exceeded
+}
+
+// FormatStats returns the "Graph Statistics" section as printed after the
+// summary.
+func FormatStats(stats GraphStats) string {
+	var sb strings.Builder
+	sb.WriteString("Graph Statistics:\n")
+	fmt.Fprintf(&sb, "  Internal plugins:      %d\n", stats.InternalPlugins)
+	fmt.Fprintf(&sb, "  External dependencies: %d\n", stats.ExternalDependencies)
+	fmt.Fprintf(&sb, "  Edges:                 %d\n", stats.Edges)
+	fmt.Fprintf(&sb, "  Cycles:                %d\n", stats.Cycles)
+	fmt.Fprintf(&sb, "  Max depth:             %d\n", stats.MaxDepth)
+	if stats.MostDependedUpon == "" {
+		sb.WriteString("  Most depended upon:    none\n")
+	} else {
+		fmt.Fprintf(&sb, "  Most depended upon:    %s (%d dependents)\n", stats.MostDependedUpon, stats.MostDependedUponFanIn)
+	}
+	return sb.String()
+}
+
+// SummaryLine returns the key figures of the graph as a single line of
+// key=value pairs for scripts, e.g.
+// "plugins=42 external=17 edges=88 cycles=0 orphans=3". Orphans are the
+// isolated plugins of Orphans.
+func (pa *PluginAnalyzer) SummaryLine() string {
+	stats := pa.Stats()
+	return fmt.Sprintf("plugins=%d external=%d edges=%d cycles=%d orphans=%d",
+		stats.InternalPlugins, stats.ExternalDependencies, stats.Edges, stats.Cycles, len(pa.Orphans()))
+}
+
+// GenerateStatsJSON returns the statistics as an indented JSON object.
+func GenerateStatsJSON(stats GraphStats) ([]byte, error) {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,28 @@
+package analyzer
+
+import "testing"
+
+func TestStatsMaxDepthWithCycle(t *testing.T) {
+	pa, _ := scanFixture(t, map[string]string{
+		"A": `{"name": "v/a", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b", "require": {"v/c": "*"}}`,
+		"C": `{"name": "v/c", "require": {"v/b": "*", "v/d": "*"}}`,
+		"D": `{"name": "v/d"}`,
+	})
+	stats := pa.Stats()
+	if stats.Cycles != 1 {
+		t.Errorf("Cycles = %d, want 1", stats.Cycles)
+	}
+	if stats.MaxDepth != 2 {
+		t.Errorf("MaxDepth = %d, want 2", stats.MaxDepth)
+	}
+}
+
+func TestStatsBudgetExceeded(t *testing.T) {
+	stats := GraphStats{Edges: 95, Cycles: 1, MaxDepth: 2}
+	budget := StatsBudget{MaxEdges: 90, MaxCycles: -1, MaxDepth: 2}
+	got := budget.Exceeded(stats)
+	if len(got) != 1 || got[0] != "edges: 95 > 90" {
+		t.Errorf("Exceeded() = %q, want [\"edges: 95 > 90\"]", got)
+	}
+}
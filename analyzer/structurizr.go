@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// structurizrString quotes s as a Structurizr DSL string.
+func structurizrString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// structurizrIDs assigns every node a DSL identifier derived from its
+// composer name, e.g. topdata_plugin_a, made unique with a numeric suffix.
+func structurizrIDs(names []string) map[string]string {
+	ids := make(map[string]string)
+	used := make(map[string]bool)
+	for _, name := range names {
+		base := strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, name)
+		id := base
+		for i := 2; used[id]; i++ {
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[id] = true
+		ids[name] = id
+	}
+	return ids
+}
+
+// GenerateStructurizr returns a Structurizr DSL workspace for the graph.
+// Internal plugins are containers of a "Shopware" software system, external
+// packages are software systems tagged External, and every dependency is a
+// relationship described by its kind and constraint. The container view
+// lays itself out automatically.
+func (pa *PluginAnalyzer) GenerateStructurizr() string {
+	var names []string
+	for name, plugin := range pa.Plugins {
+		if plugin.IsExternal && !pa.ShowExternalDeps {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ids := structurizrIDs(names)
+
+	title := pa.Title
+	if title == "" {
+		title = "Plugin Dependencies"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("workspace %s {\n", structurizrString(title)))
+	sb.WriteString("    model {\n")
+	sb.WriteString("        shopware = softwareSystem \"Shopware\" {\n")
+	for _, name := range names {
+		plugin := pa.Plugins[name]
+		if !plugin.IsExternal {
+			sb.WriteString(fmt.Sprintf("            %s = container %s %s %s\n", ids[name],
+				structurizrString(plugin.FolderName), structurizrString(plugin.Name), structurizrString(strings.TrimSpace("Shopware plugin "+plugin.Version))))
+		}
+	}
+	sb.WriteString("        }\n")
+	for _, name := range names {
+		if plugin := pa.Plugins[name]; plugin.IsExternal {
+			sb.WriteString(fmt.Sprintf("        %s = softwareSystem %s \"External package\" \"External\"\n", ids[name], structurizrString(name)))
+		}
+	}
+
+	sb.WriteString("\n")
+	for _, name := range names {
+		deps := append([]Dependency(nil), pa.Plugins[name].Dependencies...)
+		sort.Slice(deps, func(i, j int) bool {
+			if deps[i].Name != deps[j].Name {
+				return deps[i].Name < deps[j].Name
+			}
+			return kindOrder[deps[i].Kind] < kindOrder[deps[j].Kind]
+		})
+		for _, dep := range deps {
+			if _, ok := ids[dep.Name]; !ok {
+				continue
+			}
+			description := strings.TrimSpace(string(dep.Kind) + " " + dep.Constraint)
+			sb.WriteString(fmt.Sprintf("        %s -> %s %s\n", ids[name], ids[dep.Name], structurizrString(description)))
+		}
+	}
+	sb.WriteString("    }\n\n")
+
+	sb.WriteString("    views {\n")
+	sb.WriteString("        container shopware \"PluginDependencies\" {\n")
+	sb.WriteString("            include *\n")
+	sb.WriteString("            autoLayout\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("        styles {\n")
+	sb.WriteString("            element \"External\" {\n")
+	sb.WriteString("                background #ffe0e0\n")
+	sb.WriteString("            }\n")
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+	return sb.String()
+}
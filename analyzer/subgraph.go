@@ -0,0 +1,148 @@
+package analyzer
+
+import "fmt"
+
+// subset returns a copy of the analyzer containing only the plugins in keep
+// and the edges between them. External usage counts are recomputed from the
+// kept plugins. All generators work unchanged on the result.
+func (pa *PluginAnalyzer) subset(keep map[string]bool) *PluginAnalyzer {
+	sub := *pa
+	sub.Plugins = make(map[string]*Plugin)
+	for name, plugin := range pa.Plugins {
+		if !keep[name] {
+			continue
+		}
+		copied := *plugin
+		copied.Dependencies = nil
+		for _, dep := range plugin.Dependencies {
+			if keep[dep.Name] {
+				copied.Dependencies = append(copied.Dependencies, dep)
+			}
+		}
+		sub.Plugins[name] = &copied
+	}
+
+	sub.ExternalDepsCount = make(map[string]int)
+	sub.externalUsers = make(map[string]map[string]bool)
+	for dep, users := range pa.externalUsers {
+		for user := range users {
+			if keep[user] {
+				sub.countExternalUse(dep, sub.Plugins[user])
+			}
+		}
+	}
+
+	return &sub
+}
+
+// VendorScope returns the subgraph relevant to one vendor: all of its
+// plugins plus one hop in each direction, i.e. everything they depend on
+// and every plugin depending on them.
+func (pa *PluginAnalyzer) VendorScope(vendor string) (*PluginAnalyzer, error) {
+	keep := make(map[string]bool)
+	for _, name := range pa.InternalPluginNames() {
+		if vendorOf(name) != vendor {
+			continue
+		}
+		keep[name] = true
+		for _, dep := range pa.Plugins[name].Dependencies {
+			keep[dep.Name] = true
+		}
+		for _, dependent := range pa.directDependents(name) {
+			keep[dependent] = true
+		}
+	}
+
+	if len(keep) == 0 {
+		return nil, fmt.Errorf("no plugins of vendor %q found", vendor)
+	}
+	return pa.subset(keep), nil
+}
+
+// RootScope returns the subgraph needed to ship one plugin: the plugin,
+// every internal plugin it transitively depends on, and the external
+// packages any of them require directly.
+func (pa *PluginAnalyzer) RootScope(name string) (*PluginAnalyzer, error) {
+	root := pa.FindPlugin(name)
+	if root == nil {
+		return nil, fmt.Errorf("plugin %q not found", name)
+	}
+
+	return pa.shipScope([]string{root.Name}), nil
+}
+
+// shipScope returns the plugins with the given composer names, every
+// internal plugin they transitively depend on, and the external packages
+// any of them require directly.
+func (pa *PluginAnalyzer) shipScope(names []string) *PluginAnalyzer {
+	keep := make(map[string]bool)
+	for _, name := range names {
+		keep[name] = true
+		for _, dep := range pa.TransitiveDependencies(name) {
+			keep[dep] = true
+		}
+	}
+	for name := range keep {
+		for _, dep := range pa.Plugins[name].Dependencies {
+			if dep.Kind != KindSuggest && pa.Plugins[dep.Name].IsExternal {
+				keep[dep.Name] = true
+			}
+		}
+	}
+	return pa.subset(keep)
+}
+
+// Subgraph returns the neighborhood of one plugin: the plugin plus every
+// node reachable within depth hops along dependencies and every plugin
+// reaching it within depth hops. Depth 0 keeps only the plugin itself.
+func (pa *PluginAnalyzer) Subgraph(root string, depth int) (*PluginAnalyzer, error) {
+	return pa.SubgraphOf([]string{root}, depth)
+}
+
+// SubgraphOf returns the union of the neighborhoods of several plugins, as
+// Subgraph does for one.
+func (pa *PluginAnalyzer) SubgraphOf(roots []string, depth int) (*PluginAnalyzer, error) {
+	if depth < 0 {
+		return nil, fmt.Errorf("depth must not be negative, got %d", depth)
+	}
+	keep := make(map[string]bool)
+	for _, root := range roots {
+		plugin := pa.FindPlugin(root)
+		if plugin == nil {
+			return nil, fmt.Errorf("plugin %q not found", root)
+		}
+		pa.addNeighborhood(keep, plugin.Name, depth)
+	}
+	return pa.subset(keep), nil
+}
+
+// addNeighborhood adds name and the nodes within depth hops of it in either
+// direction to keep.
+func (pa *PluginAnalyzer) addNeighborhood(keep map[string]bool, name string, depth int) {
+	keep[name] = true
+	walk := func(next func(name string) []string) {
+		frontier := []string{name}
+		seen := map[string]bool{name: true}
+		for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+			var following []string
+			for _, current := range frontier {
+				for _, n := range next(current) {
+					if !seen[n] {
+						seen[n] = true
+						keep[n] = true
+						following = append(following, n)
+					}
+				}
+			}
+			frontier = following
+		}
+	}
+	walk(func(name string) []string {
+		var deps []string
+		for _, dep := range pa.Plugins[name].Dependencies {
+			deps = append(deps, dep.Name)
+		}
+		return deps
+	})
+	walk(pa.directDependents)
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Summary returns the "Internal Dependencies Summary" section: every
+// internal plugin with dependencies, followed by them.
+func (pa *PluginAnalyzer) Summary() string {
+	var sb strings.Builder
+	sb.WriteString("Internal Dependencies Summary:\n")
+	for _, plugin := range pa.sortedPlugins() {
+		if plugin.IsExternal || len(plugin.Dependencies) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s %s (Shopware %s):\n", plugin.FolderName, VersionLabel(plugin), plugin.ShopwareVersion)
+		for _, dep := range plugin.Dependencies {
+			depPlugin := pa.Plugins[dep.Name]
+			kind := ""
+			if dep.Kind != KindRequire {
+				kind = fmt.Sprintf(" (%s)", dep.Kind)
+			}
+			if depPlugin.IsExternal {
+				fmt.Fprintf(&sb, "  ├─ %s (external)%s\n", dep.Name, kind)
+			} else {
+				fmt.Fprintf(&sb, "  ├─ %s%s\n", depPlugin.FolderName, kind)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// ExternalSummary returns the "External Dependencies Summary" section with
+// the number of plugins using each external package, or "" if there are
+// none.
+func (pa *PluginAnalyzer) ExternalSummary() string {
+	if len(pa.ExternalDepsCount) == 0 {
+		return ""
+	}
+	deps := make([]string, 0, len(pa.ExternalDepsCount))
+	for dep := range pa.ExternalDepsCount {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+
+	var sb strings.Builder
+	sb.WriteString("External Dependencies Summary:\n")
+	for _, dep := range deps {
+		fmt.Fprintf(&sb, "  %s: used by %d plugin(s)\n", dep, pa.ExternalDepsCount[dep])
+	}
+	return sb.String()
+}
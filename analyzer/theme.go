@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Theme holds the colors and node shape of the Graphviz output. Fields left
+// out of a theme file keep their defaults, which match the built-in look.
+type Theme struct {
+	InternalFill     string `json:"internalFill"`
+	ExternalFill     string `json:"externalFill"`
+	DevFill          string `json:"devFill"` // nodes only required through require-dev; empty keeps the internal or external fill
+	EdgeColor        string `json:"edgeColor"`
+	DevEdgeColor     string `json:"devEdgeColor"` // empty keeps edgeColor
+	SuggestEdgeColor string `json:"suggestEdgeColor"`
+	NodeShape        string `json:"nodeShape"`
+}
+
+// defaultTheme returns the built-in colors and shape.
+func defaultTheme() *Theme {
+	return &Theme{
+		InternalFill:     "#f0f0f0",
+		ExternalFill:     "#ffe0e0",
+		EdgeColor:        "#666666",
+		SuggestEdgeColor: "#999999",
+		NodeShape:        "box",
+	}
+}
+
+// themeColor matches #rrggbb, #rrggbbaa and Graphviz color names such as
+// lightblue or gray90.
+var themeColor = regexp.MustCompile(`^(#[0-9a-fA-F]{6}([0-9a-fA-F]{2})?|[a-zA-Z]+[0-9]*)$`)
+
+// themeShapes are the Graphviz node shapes a theme may use.
+var themeShapes = []string{"box", "rect", "rectangle", "square", "ellipse", "oval", "circle",
+	"plaintext", "plain", "note", "tab", "folder", "box3d", "component", "cylinder",
+	"hexagon", "octagon", "diamond", "parallelogram", "house"}
+
+// LoadTheme reads a JSON theme file on top of the default theme and
+// validates its colors and shape.
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	theme := defaultTheme()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(theme); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := theme.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return theme, nil
+}
+
+// validate checks every color and the node shape.
+func (t *Theme) validate() error {
+	colors := []struct {
+		key, value string
+		optional   bool
+	}{
+		{"internalFill", t.InternalFill, false},
+		{"externalFill", t.ExternalFill, false},
+		{"devFill", t.DevFill, true},
+		{"edgeColor", t.EdgeColor, false},
+		{"devEdgeColor", t.DevEdgeColor, true},
+		{"suggestEdgeColor", t.SuggestEdgeColor, false},
+	}
+	for _, c := range colors {
+		if c.value == "" && c.optional {
+			continue
+		}
+		if !themeColor.MatchString(c.value) {
+			return fmt.Errorf("%s %q is not a color (expected #rrggbb, #rrggbbaa or a Graphviz color name like lightblue)", c.key, c.value)
+		}
+	}
+	for _, shape := range themeShapes {
+		if t.NodeShape == shape {
+			return nil
+		}
+	}
+	return fmt.Errorf("nodeShape %q is not supported (expected one of %s)", t.NodeShape, strings.Join(themeShapes, ", "))
+}
+
+// theme returns the configured Theme or the default one.
+func (pa *PluginAnalyzer) theme() *Theme {
+	if pa.Theme != nil {
+		return pa.Theme
+	}
+	return defaultTheme()
+}
+
+// devOnlyNodes returns the nodes that are required, but only through
+// require-dev edges.
+func (pa *PluginAnalyzer) devOnlyNodes() map[string]bool {
+	devOnly := make(map[string]bool)
+	required := make(map[string]bool)
+	for _, plugin := range pa.Plugins {
+		for _, dep := range plugin.Dependencies {
+			switch dep.Kind {
+			case KindRequire, KindBundle:
+				required[dep.Name] = true
+			case KindRequireDev:
+				devOnly[dep.Name] = true
+			}
+		}
+	}
+	for name := range required {
+		delete(devOnly, name)
+	}
+	return devOnly
+}
@@ -0,0 +1,43 @@
+package analyzer
+
+import "strings"
+
+// Tightness of a version constraint, from the loosest to the tightest. The
+// zero value stands for a constraint that couldn't be parsed.
+const (
+	tightnessAny   = iota + 1 // no upper bound, e.g. "*", ">=1.0" or a branch
+	tightnessRange            // a range of versions, e.g. "^1.2", "~1.2.0" or "1.2.*"
+	tightnessExact            // a single version, e.g. "1.2.3"
+)
+
+// tightnessColors are the Graphviz edge colors of ColorByConstraint: exact
+// pins red, ranges amber and open constraints green.
+var tightnessColors = map[int]string{
+	tightnessAny:   "#2e9e44",
+	tightnessRange: "#d4a017",
+	tightnessExact: "#d00000",
+}
+
+// constraintTightness classifies a composer version constraint by how many
+// versions it allows. A constraint is only as tight as its loosest
+// alternative, so "1.2.3 || ^2.0" is a range. It returns 0 for an empty or
+// invalid constraint.
+func constraintTightness(s string) int {
+	if strings.TrimSpace(s) == "" {
+		return 0
+	}
+	c, err := parseConstraint(s)
+	if err != nil || len(c) == 0 {
+		return 0
+	}
+	tightness := tightnessExact
+	for _, r := range c {
+		switch {
+		case !r.hasMax:
+			return tightnessAny
+		case !r.hasMin || r.min.compare(r.max) != 0:
+			tightness = tightnessRange
+		}
+	}
+	return tightness
+}
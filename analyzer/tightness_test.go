@@ -0,0 +1,27 @@
+package analyzer
+
+import "testing"
+
+func TestConstraintTightness(t *testing.T) {
+	for _, tt := range []struct {
+		constraint string
+		want       int
+	}{
+		{"*", tightnessAny},
+		{">=1.0", tightnessAny},
+		{"^1.2", tightnessRange},
+		{"~1.2.3", tightnessRange},
+		{"1.2.*", tightnessRange},
+		{">=1 <2", tightnessRange},
+		{"1.2.3", tightnessExact},
+		{"1.2.3 || 1.2.4", tightnessExact},
+		{"1.2.3 || ^2.0", tightnessRange},
+		{"^1.0 || *", tightnessAny},
+		{"", 0},
+		{"not a version", 0},
+	} {
+		if got := constraintTightness(tt.constraint); got != tt.want {
+			t.Errorf("constraintTightness(%q) = %d, want %d", tt.constraint, got, tt.want)
+		}
+	}
+}
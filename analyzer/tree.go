@@ -0,0 +1,60 @@
+package analyzer
+
+import "strings"
+
+// GenerateTree renders the transitive internal dependencies of every root
+// plugin, one without internal dependents, as an indented tree with
+// box-drawing connectors. A plugin whose dependencies were already shown is
+// marked "(*)" instead of being expanded again, and a dependency leading
+// back to a plugin on the current path is marked "(cycle)". Plugins only
+// reachable through a cycle get a tree of their own after the roots.
+func (pa *PluginAnalyzer) GenerateTree() string {
+	var sb strings.Builder
+	expanded := make(map[string]bool)
+	onPath := make(map[string]bool)
+
+	var walk func(name, prefix string)
+	walk = func(name, prefix string) {
+		expanded[name] = true
+		onPath[name] = true
+		deps := pa.internalDependencies(pa.Plugins[name])
+		for i, dep := range deps {
+			connector, indent := "├─ ", "│  "
+			if i == len(deps)-1 {
+				connector, indent = "└─ ", "   "
+			}
+			sb.WriteString(prefix + connector + pa.Plugins[dep].FolderName)
+			switch {
+			case onPath[dep]:
+				sb.WriteString(" (cycle)\n")
+			case expanded[dep] && len(pa.internalDependencies(pa.Plugins[dep])) > 0:
+				sb.WriteString(" (*)\n")
+			default:
+				sb.WriteString("\n")
+				walk(dep, prefix+indent)
+			}
+		}
+		delete(onPath, name)
+	}
+
+	metrics := pa.Metrics()
+	var roots, rest []string
+	for _, name := range pa.InternalPluginNames() {
+		if metrics[name].FanIn == 0 {
+			roots = append(roots, name)
+		} else {
+			rest = append(rest, name)
+		}
+	}
+	for _, name := range roots {
+		sb.WriteString(pa.Plugins[name].FolderName + "\n")
+		walk(name, "")
+	}
+	for _, name := range rest {
+		if !expanded[name] {
+			sb.WriteString(pa.Plugins[name].FolderName + "\n")
+			walk(name, "")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// PossibleTypo is an external requirement whose name is suspiciously close
+// to the name of an internal plugin.
+type PossibleTypo struct {
+	Plugin     string // folder of the requiring plugin
+	Required   string // the external package name as written
+	Suggestion string // the internal plugin it probably meant
+	Distance   int
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// PossibleTypos compares every external requirement with the internal
+// plugin names and returns those within maxDistance edits of one, which
+// usually means an internal require was misspelled.
+func (pa *PluginAnalyzer) PossibleTypos(maxDistance int) []PossibleTypo {
+	if maxDistance <= 0 {
+		return nil
+	}
+
+	internal := pa.InternalPluginNames()
+	var typos []PossibleTypo
+	for pkg, reqs := range pa.requirements() {
+		if plugin, ok := pa.Plugins[pkg]; ok && !plugin.IsExternal {
+			continue
+		}
+
+		best, bestDistance := "", maxDistance+1
+		for _, name := range internal {
+			if d := levenshtein(strings.ToLower(pkg), strings.ToLower(name)); d < bestDistance {
+				best, bestDistance = name, d
+			}
+		}
+		if best == "" {
+			continue
+		}
+		for _, req := range reqs {
+			typos = append(typos, PossibleTypo{Plugin: req.Plugin, Required: pkg, Suggestion: best, Distance: bestDistance})
+		}
+	}
+
+	sort.Slice(typos, func(i, j int) bool {
+		if typos[i].Plugin != typos[j].Plugin {
+			return typos[i].Plugin < typos[j].Plugin
+		}
+		return typos[i].Required < typos[j].Required
+	})
+	return typos
+}
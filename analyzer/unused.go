@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sourceExtensions are the files searched for namespace references: PHP
+// code and the XML service definitions wiring it up.
+var sourceExtensions = map[string]bool{".php": true, ".xml": true}
+
+// UnusedDependency is an internal dependency whose namespace the dependent
+// plugin's source never mentions.
+type UnusedDependency struct {
+	Plugin string // folder of the depending plugin
+	Target string // folder of the required plugin
+}
+
+// psr4Namespaces returns the PSR-4 namespace prefixes of an autoload
+// section without their trailing backslash, sorted.
+func psr4Namespaces(autoload ComposerAutoload) []string {
+	var namespaces []string
+	for ns := range autoload.PSR4 {
+		if ns = strings.TrimRight(ns, `\`); ns != "" {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// readSources concatenates the PHP and XML files below dir, skipping
+// vendor and node_modules directories.
+func readSources(dir string) (string, error) {
+	var sb strings.Builder
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if name := d.Name(); name == "vendor" || name == "node_modules" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !sourceExtensions[filepath.Ext(path)] {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sb.Write(data)
+		sb.WriteByte('\n')
+		return nil
+	})
+	return sb.String(), err
+}
+
+// referencesNamespace reports whether source mentions a class below ns,
+// either as PHP code (Vendor\Plugin\...) or escaped in a string
+// (Vendor\\Plugin\\...).
+func referencesNamespace(source, ns string) bool {
+	return strings.Contains(source, ns+`\`) ||
+		strings.Contains(source, strings.ReplaceAll(ns, `\`, `\\`)+`\\`)
+}
+
+// UnusedDependencies returns the internal dependencies whose PSR-4
+// namespaces never appear in the PHP or XML files of the depending plugin,
+// sorted by plugin and target. Dependencies on plugins without a PSR-4
+// autoload section can't be checked and are skipped. This is a heuristic:
+// a dependency may also be needed for assets, configuration or events
+// matched by name.
+func (pa *PluginAnalyzer) UnusedDependencies() ([]UnusedDependency, error) {
+	var unused []UnusedDependency
+	for _, name := range pa.InternalPluginNames() {
+		plugin := pa.Plugins[name]
+		deps := pa.internalDependencies(plugin)
+		if len(deps) == 0 {
+			continue
+		}
+
+		source, err := readSources(filepath.Join(plugin.Dir, plugin.FolderPath))
+		if err != nil {
+			return nil, err
+		}
+		for _, dep := range deps {
+			target := pa.Plugins[dep]
+			if len(target.Namespaces) == 0 {
+				continue
+			}
+			used := false
+			for _, ns := range target.Namespaces {
+				if referencesNamespace(source, ns) {
+					used = true
+					break
+				}
+			}
+			if !used {
+				unused = append(unused, UnusedDependency{Plugin: plugin.FolderName, Target: target.FolderName})
+			}
+		}
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		if unused[i].Plugin != unused[j].Plugin {
+			return unused[i].Plugin < unused[j].Plugin
+		}
+		return unused[i].Target < unused[j].Target
+	})
+	return unused, nil
+}
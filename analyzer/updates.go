@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const packagistURL = "https://repo.packagist.org/p2/%s.json"
+
+// OutdatedPackage is an external dependency whose latest stable release is
+// excluded by the constraint of at least one requiring plugin.
+type OutdatedPackage struct {
+	Package      string
+	Latest       string
+	Requirements []Requirement // only the requirements excluding Latest
+}
+
+// PackagistClient looks up package releases on packagist.org, caching the
+// responses on disk for cacheTTL.
+type PackagistClient struct {
+	httpClient *http.Client
+	cacheDir   string
+	cacheTTL   time.Duration
+}
+
+// NewPackagistClient returns a client whose HTTP requests time out after
+// timeout.
+func NewPackagistClient(timeout time.Duration) *PackagistClient {
+	cacheDir := ""
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = filepath.Join(dir, "sw6-plugin-analyzer", "packagist")
+	}
+	return &PackagistClient{
+		httpClient: &http.Client{Timeout: timeout},
+		cacheDir:   cacheDir,
+		cacheTTL:   24 * time.Hour,
+	}
+}
+
+type packagistResponse struct {
+	Packages map[string][]struct {
+		Version string `json:"version"`
+	} `json:"packages"`
+}
+
+// fetch returns the packagist metadata of pkg, from the cache if fresh.
+func (c *PackagistClient) fetch(pkg string) ([]byte, error) {
+	cacheFile := ""
+	if c.cacheDir != "" {
+		cacheFile = filepath.Join(c.cacheDir, strings.ReplaceAll(pkg, "/", "~")+".json")
+		if info, err := os.Stat(cacheFile); err == nil && time.Since(info.ModTime()) < c.cacheTTL {
+			if data, err := os.ReadFile(cacheFile); err == nil {
+				return data, nil
+			}
+		}
+	}
+
+	resp, err := c.httpClient.Get(fmt.Sprintf(packagistURL, pkg))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("packagist returned %s for %s", resp.Status, pkg)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid packagist response for %s: %w", pkg, err)
+	}
+
+	if cacheFile != "" {
+		if err := os.MkdirAll(c.cacheDir, 0755); err == nil {
+			os.WriteFile(cacheFile, raw, 0644)
+		}
+	}
+	return raw, nil
+}
+
+// latestVersion returns the highest stable release of pkg.
+func (c *PackagistClient) latestVersion(pkg string) (string, error) {
+	data, err := c.fetch(pkg)
+	if err != nil {
+		return "", err
+	}
+
+	var resp packagistResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", fmt.Errorf("invalid packagist response for %s: %w", pkg, err)
+	}
+
+	latest, latestRaw := version{}, ""
+	for _, release := range resp.Packages[pkg] {
+		if !isStableVersion(release.Version) {
+			continue
+		}
+		v, _, err := parseVersion(release.Version)
+		if err != nil {
+			continue
+		}
+		if latestRaw == "" || v.compare(latest) > 0 {
+			latest, latestRaw = v, release.Version
+		}
+	}
+	if latestRaw == "" {
+		return "", fmt.Errorf("no stable release of %s found", pkg)
+	}
+	return latestRaw, nil
+}
+
+// isStableVersion reports whether a release is neither a branch nor a
+// pre-release.
+func isStableVersion(v string) bool {
+	v = strings.ToLower(v)
+	if strings.HasPrefix(v, "dev-") || strings.HasSuffix(v, "-dev") {
+		return false
+	}
+	for _, marker := range []string{"alpha", "beta", "rc", "patch"} {
+		if strings.Contains(v, marker) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckUpdates queries packagist for the latest release of every external
+// dependency and returns those whose newest release falls outside the
+// constraint of a requiring plugin. The result is also recorded in
+// pa.Outdated so that the graph can mark the affected nodes.
+func (pa *PluginAnalyzer) CheckUpdates(client *PackagistClient) []OutdatedPackage {
+	var outdated []OutdatedPackage
+	pa.Outdated = make(map[string]string)
+
+	for pkg, reqs := range pa.requirements() {
+		if plugin, ok := pa.Plugins[pkg]; (ok && !plugin.IsExternal) || pa.isInternalName(pkg) || isPlatformPackage(pkg) {
+			continue
+		}
+
+		latest, err := client.latestVersion(pkg)
+		if err != nil {
+			pa.warnf("Could not check updates for %s: %v", pkg, err)
+			continue
+		}
+		v, _, _ := parseVersion(latest)
+
+		var excluding []Requirement
+		for _, req := range reqs {
+			c, err := parseConstraint(req.Constraint)
+			if err == nil && !c.allows(v) {
+				excluding = append(excluding, req)
+			}
+		}
+		if len(excluding) == 0 {
+			continue
+		}
+
+		sort.Slice(excluding, func(i, j int) bool { return excluding[i].Plugin < excluding[j].Plugin })
+		outdated = append(outdated, OutdatedPackage{Package: pkg, Latest: latest, Requirements: excluding})
+		pa.Outdated[pkg] = latest
+	}
+
+	sort.Slice(outdated, func(i, j int) bool { return outdated[i].Package < outdated[j].Package })
+	return outdated
+}
@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shopwarePluginTypes are the composer types that mark a package in a
+// vendor/ tree as a Shopware plugin.
+var shopwarePluginTypes = map[string]bool{
+	"shopware-platform-plugin": true,
+	"shopware-bundle":          true,
+}
+
+func isShopwarePluginType(composerType string) bool {
+	return shopwarePluginTypes[composerType]
+}
+
+// normalizeFolderName converts the separators of a relative folder name to
+// forward slashes whatever the OS, so node labels and IDs come out the same
+// on Windows as elsewhere. Backslashes are replaced explicitly because
+// filepath.ToSlash only touches the separator of the running OS. The result
+// is only a label: on POSIX a backslash may be part of a real folder name,
+// so files are always accessed through the folder as found on disk.
+func normalizeFolderName(folder string) string {
+	return strings.ReplaceAll(filepath.ToSlash(folder), `\`, "/")
+}
+
+// pluginFolders returns the plugin folders below dir, relative to it
+// and with the separators of the OS. In the custom/plugins layout these are its
+// direct subdirectories. In a vendor/ tree they are the <vendor>/<package>
+// directories containing a composer.json; the folder name then is
+// "<vendor>/<package>". With Recursive, nested plugins are found as well.
+func (pa *PluginAnalyzer) pluginFolders(dir string) ([]string, error) {
+	if pa.Recursive {
+		return nestedPluginFolders(dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var folders []string
+	for _, entry := range entries {
+		if !isDirEntry(dir, entry) {
+			continue
+		}
+		if !pa.VendorLayout {
+			folders = append(folders, entry.Name())
+			continue
+		}
+
+		packages, err := os.ReadDir(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, pkg := range packages {
+			folder := filepath.Join(entry.Name(), pkg.Name())
+			// vendor/bin, vendor/composer and the like hold no packages.
+			if _, err := os.Stat(filepath.Join(dir, folder, "composer.json")); err == nil {
+				folders = append(folders, folder)
+			}
+		}
+	}
+	return folders, nil
+}
+
+// skippedDirs are never searched for nested plugins.
+var skippedDirs = map[string]bool{"vendor": true, "node_modules": true}
+
+// isDirEntry reports whether a directory entry of parent is a directory,
+// following symlinks, which ReadDir reports as such rather than as the
+// directory they point to.
+func isDirEntry(parent string, entry os.DirEntry) bool {
+	if entry.Type()&os.ModeSymlink == 0 {
+		return entry.IsDir()
+	}
+	info, err := os.Stat(filepath.Join(parent, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+// nestedPluginFolders walks the subdirectories of dir for folders holding a
+// composer.json of a Shopware plugin type, e.g. "Bundles/MyPlugin". The walk
+// doesn't descend below a folder with a composer.json, so packages bundled
+// inside a plugin are not picked up. A composer.json that can't be read or
+// parsed still counts, so that scanning reports the problem. Symlinked
+// directories are followed; each real directory, plugin folders included,
+// is visited once, so symlink loops end and a plugin reachable through a
+// symlink is not found twice.
+func nestedPluginFolders(dir string) ([]string, error) {
+	var folders []string
+	visited := make(map[string]bool)
+	firstVisit := func(path string) bool {
+		real, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			real, err = filepath.Abs(real)
+		}
+		if err != nil {
+			return true
+		}
+		if visited[real] {
+			return false
+		}
+		visited[real] = true
+		return true
+	}
+
+	var walk func(rel string) error
+	walk = func(rel string) error {
+		path := filepath.Join(dir, rel)
+		if !firstVisit(path) {
+			return nil
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if !isDirEntry(path, entry) || skippedDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			child := filepath.Join(rel, entry.Name())
+
+			data, err := os.ReadFile(filepath.Join(dir, child, "composer.json"))
+			if os.IsNotExist(err) {
+				if err := walk(child); err != nil {
+					return err
+				}
+				continue
+			}
+			var composer ComposerJSON
+			if err == nil {
+				data, _, err = ToUTF8(data)
+			}
+			if err == nil {
+				err = json.Unmarshal(data, &composer)
+			}
+			if (err != nil || isShopwarePluginType(composer.Type)) && firstVisit(filepath.Join(dir, child)) {
+				folders = append(folders, child)
+			}
+		}
+		return nil
+	}
+
+	err := walk("")
+	return folders, err
+}
@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestNormalizeFolderName(t *testing.T) {
+	for _, tt := range []struct{ folder, want string }{
+		{"MyPlugin", "MyPlugin"},
+		{`Bundles\MyPlugin`, "Bundles/MyPlugin"},
+		{`vendor\acme\plugin`, "vendor/acme/plugin"},
+		{"Bundles/MyPlugin", "Bundles/MyPlugin"},
+	} {
+		if got := normalizeFolderName(tt.folder); got != tt.want {
+			t.Errorf("normalizeFolderName(%q) = %q, want %q", tt.folder, got, tt.want)
+		}
+	}
+}
+
+func TestBackslashFolderIsReadFromDisk(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("a backslash is a separator on Windows")
+	}
+	pa, logs := scanFixture(t, map[string]string{
+		`Odd\Name`: `{"name": "t/odd", "type": "shopware-platform-plugin"}`,
+	})
+	plugin := pa.Plugins["t/odd"]
+	if plugin == nil {
+		t.Fatalf("plugin not scanned; log:\n%s", logs)
+	}
+	if plugin.FolderName != "Odd/Name" || plugin.FolderPath != `Odd\Name` {
+		t.Errorf("FolderName, FolderPath = %q, %q, want %q, %q", plugin.FolderName, plugin.FolderPath, "Odd/Name", `Odd\Name`)
+	}
+	if _, err := os.Stat(pa.composerPath(plugin)); err != nil {
+		t.Errorf("composerPath: %v", err)
+	}
+}
+
+func TestNestedPluginFoldersSymlinks(t *testing.T) {
+	dir := writePlugins(t, map[string]string{
+		"Bundles/X": `{"name": "t/x", "type": "shopware-platform-plugin"}`,
+	})
+	// A loop back to the plugins directory and a second path to the plugin.
+	for link, target := range map[string]string{"loop": ".", "alias": "Bundles/X"} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	folders, err := nestedPluginFolders(dir)
+	if err != nil {
+		t.Fatalf("nestedPluginFolders: %v", err)
+	}
+	if want := []string{"Bundles/X"}; !reflect.DeepEqual(folders, want) {
+		t.Errorf("nestedPluginFolders() = %q, want %q", folders, want)
+	}
+
+	pa, logs := newTestAnalyzer(dir)
+	pa.Recursive = true
+	if err := pa.ScanPlugins(); err != nil {
+		t.Fatalf("ScanPlugins: %v", err)
+	}
+	if len(pa.Plugins) != 1 || logs.Len() > 0 {
+		t.Errorf("scanned %d plugins, want 1; log:\n%s", len(pa.Plugins), logs)
+	}
+}
@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchInterval is how often WatchPlugins polls for changes, and how long
+// NotifyPluginsUntil waits for the files to settle after a change.
+const WatchInterval = time.Second
+
+// watchedFiles are the files per plugin folder whose changes trigger a rescan.
+var watchedFiles = []string{"composer.json", pluginMetaFile, pluginXMLFile}
+
+// Rescan returns a new analyzer with the same configuration that has
+// scanned the plugins directories again.
+func (pa *PluginAnalyzer) Rescan() (*PluginAnalyzer, error) {
+	fresh := *pa
+	fresh.Plugins = make(map[string]*Plugin)
+	fresh.ExternalDepsCount = make(map[string]int)
+	fresh.externalUsers = nil
+	fresh.excluded = nil
+	fresh.libraries = nil
+	fresh.replacedBy = nil
+	fresh.providedBy = nil
+	fresh.duplicates = nil
+	fresh.ScanErrors = nil
+	if err := fresh.ScanPlugins(); err != nil {
+		return nil, err
+	}
+	return &fresh, nil
+}
+
+// fingerprint summarizes the plugin folders and the size and modification
+// time of their watched files; it changes whenever a rescan could.
+func (pa *PluginAnalyzer) fingerprint() (string, error) {
+	var sb strings.Builder
+	for _, dir := range pa.PluginsDirs {
+		folders, err := pa.pluginFolders(dir)
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(folders)
+
+		for _, folder := range folders {
+			sb.WriteString(filepath.Join(dir, folder))
+			for _, file := range watchedFiles {
+				if info, err := os.Stat(filepath.Join(dir, folder, file)); err == nil {
+					fmt.Fprintf(&sb, "|%s:%d:%d", file, info.Size(), info.ModTime().UnixNano())
+				}
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+// WatchPlugins polls the plugins directories every interval and calls
+// onChange with a rescanned analyzer whenever a plugin folder or one of its
+// watched files changed. Polling works the same on every OS and filesystem,
+// including network mounts. It never returns.
+func WatchPlugins(pa *PluginAnalyzer, interval time.Duration, onChange func(*PluginAnalyzer)) {
+	WatchPluginsUntil(pa, interval, nil, onChange)
+}
+
+// WatchPluginsUntil is WatchPlugins returning once done is closed. A change
+// is only acted on once the folders were unchanged for a whole interval, so
+// a burst of writes, e.g. by a git checkout, causes a single rescan.
+func WatchPluginsUntil(pa *PluginAnalyzer, interval time.Duration, done <-chan struct{}, onChange func(*PluginAnalyzer)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last, _ := pa.fingerprint()
+	pending := false
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		current, err := pa.fingerprint()
+		if err != nil {
+			continue
+		}
+		if current != last {
+			last, pending = current, true
+			continue
+		}
+		if !pending {
+			continue
+		}
+		pending = false
+
+		fresh, err := pa.Rescan()
+		if err != nil {
+			pa.logger().Printf("Rescan failed: %v", err)
+			continue
+		}
+		pa = fresh
+		onChange(fresh)
+	}
+}
+
+// watchDirs lists the directories NotifyPluginsUntil watches: the plugins
+// directories, the plugin folders and every directory between them, and with
+// Recursive every directory a nested plugin may be added to.
+func (pa *PluginAnalyzer) watchDirs() ([]string, error) {
+	seen := make(map[string]bool)
+	var dirs []string
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			dirs = append(dirs, path)
+		}
+	}
+
+	for _, dir := range pa.PluginsDirs {
+		add(dir)
+		folders, err := pa.pluginFolders(dir)
+		if err != nil {
+			return nil, err
+		}
+		plugins := make(map[string]bool)
+		for _, folder := range folders {
+			plugins[filepath.Join(dir, folder)] = true
+			for f := folder; f != "." && f != string(filepath.Separator); f = filepath.Dir(f) {
+				add(filepath.Join(dir, f))
+			}
+		}
+		if !pa.Recursive {
+			continue
+		}
+
+		err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.IsDir() {
+				return nil
+			}
+			if path != dir && (skippedDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			add(path)
+			if plugins[path] {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// NotifyPluginsUntil is WatchPluginsUntil driven by file system
+// notifications instead of polling. It calls onChange with a rescanned
+// analyzer once the watched files were left alone for debounce after a
+// change, and returns once done is closed. It returns an error if the
+// watches can't be set up, e.g. when the OS limit on them is reached.
+func NotifyPluginsUntil(pa *PluginAnalyzer, debounce time.Duration, done <-chan struct{}, onChange func(*PluginAnalyzer)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool)
+	refresh := func() error {
+		dirs, err := pa.watchDirs()
+		if err != nil {
+			return err
+		}
+		for _, dir := range dirs {
+			if watched[dir] {
+				continue
+			}
+			if err := watcher.Add(dir); err != nil {
+				return fmt.Errorf("watching %s: %w", dir, err)
+			}
+			watched[dir] = true
+		}
+		return nil
+	}
+	if err := refresh(); err != nil {
+		return err
+	}
+
+	settled := time.NewTimer(debounce)
+	settled.Stop()
+	defer settled.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			pa.logger().Printf("Watch error: %v", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if pa.relevantEvent(event, watched) {
+				settled.Reset(debounce)
+			}
+		case <-settled.C:
+			fresh, err := pa.Rescan()
+			if err != nil {
+				pa.logger().Printf("Rescan failed: %v", err)
+				continue
+			}
+			pa = fresh
+			// Watches of removed directories are dropped by the OS.
+			watched = make(map[string]bool)
+			for _, dir := range watcher.WatchList() {
+				watched[dir] = true
+			}
+			if err := refresh(); err != nil {
+				pa.logger().Printf("Watch error: %v", err)
+			}
+			onChange(fresh)
+		}
+	}
+}
+
+// relevantEvent reports whether event may change a rescan: a watched file
+// was touched, or a directory that may hold plugins was added, removed or
+// renamed.
+func (pa *PluginAnalyzer) relevantEvent(event fsnotify.Event, watched map[string]bool) bool {
+	if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+		return false
+	}
+	for _, file := range watchedFiles {
+		if filepath.Base(event.Name) == file {
+			return true
+		}
+	}
+	if watched[event.Name] {
+		return true
+	}
+	if event.Has(fsnotify.Create) {
+		info, err := os.Stat(event.Name)
+		return err == nil && info.IsDir()
+	}
+	return false
+}
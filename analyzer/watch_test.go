@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNotifyPluginsUntilRescansAddedPlugin(t *testing.T) {
+	dir := writePlugins(t, map[string]string{"A": `{"name": "vendor/a"}`})
+	pa, _ := newTestAnalyzer(dir)
+	if err := pa.ScanPlugins(); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	changed := make(chan *PluginAnalyzer, 1)
+	stopped := make(chan error)
+	go func() {
+		stopped <- NotifyPluginsUntil(pa, 50*time.Millisecond, done, func(fresh *PluginAnalyzer) {
+			select {
+			case changed <- fresh:
+			default:
+			}
+		})
+	}()
+
+	if err := os.Mkdir(filepath.Join(dir, "B"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The watches may not be set up yet, so write until a rescan picks it up.
+	timeout := time.After(5 * time.Second)
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
+	for found := false; !found; {
+		select {
+		case fresh := <-changed:
+			found = fresh.FindPlugin("B") != nil
+		case <-tick.C:
+			if err := os.WriteFile(filepath.Join(dir, "B", "composer.json"), []byte(`{"name": "vendor/b"}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+		case <-timeout:
+			t.Fatal("added plugin B was never rescanned")
+		}
+	}
+
+	close(done)
+	if err := <-stopped; err != nil {
+		t.Errorf("NotifyPluginsUntil: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// zipRoot returns the directory of the archive holding the plugin folders:
+// the shallowest one with a composer.json depth folder levels below it,
+// e.g. "custom/plugins" for an archive of custom/plugins/A/composer.json.
+// ok is false if the archive contains no such composer.json.
+func zipRoot(files []*zip.File, depth int) (root string, ok bool) {
+	best := -1
+	for _, f := range files {
+		parts := strings.Split(f.Name, "/")
+		if parts[len(parts)-1] != "composer.json" || len(parts) < depth+1 || parts[0] == "__MACOSX" {
+			continue
+		}
+		dir := parts[:len(parts)-1-depth]
+		candidate := strings.Join(dir, "/")
+		if best == -1 || len(dir) < best || (len(dir) == best && candidate < root) {
+			best, root = len(dir), candidate
+		}
+	}
+	return root, best != -1
+}
+
+// scanZip adds the internal plugins found in a zip archive of a plugins
+// directory, as scanDir does for a directory, and returns the number of
+// plugin folders in it. Folders are looked for below the shallowest
+// directory of the archive holding a plugin, so archives of custom/plugins
+// itself and of a parent directory both work. The scan cache is not used.
+func (pa *PluginAnalyzer) scanZip(file string) (int, error) {
+	archive, err := zip.OpenReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open plugins archive %s: %w", file, err)
+	}
+	defer archive.Close()
+
+	depth := 1
+	if pa.VendorLayout {
+		depth = 2
+	}
+	root, ok := zipRoot(archive.File, depth)
+	if !ok {
+		return 0, nil
+	}
+	var fsys fs.FS = archive
+	if root != "" {
+		if fsys, err = fs.Sub(archive, root); err != nil {
+			return 0, err
+		}
+	}
+
+	// Every folder below the root counts, so one without a composer.json
+	// is reported like in a directory. In a vendor/ tree only packages
+	// with a composer.json do, as vendor/bin and the like hold none.
+	seen := make(map[string]bool)
+	var folders []string
+	for _, f := range archive.File {
+		name := strings.TrimPrefix(f.Name, root+"/")
+		if root == "" {
+			name = f.Name
+		} else if name == f.Name {
+			continue
+		}
+		parts := strings.Split(name, "/")
+		if len(parts) <= depth {
+			continue
+		}
+		folder := strings.Join(parts[:depth], "/")
+		if parts[0] == "__MACOSX" || (pa.VendorLayout && name != folder+"/composer.json") {
+			continue
+		}
+		if !seen[folder] {
+			seen[folder] = true
+			folders = append(folders, folder)
+		}
+	}
+	sort.Strings(folders)
+
+	dir := filepath.Join(file, filepath.FromSlash(root))
+	paths := make([]string, len(folders))
+	results := make([]composerResult, len(folders))
+	for i, folder := range folders {
+		paths[i] = filepath.Join(dir, filepath.FromSlash(folder), "composer.json")
+		pa.debugf("Reading %s", paths[i])
+		data, err := fs.ReadFile(fsys, path.Join(folder, "composer.json"))
+		if err != nil {
+			results[i].missing = true
+			continue
+		}
+		res := &results[i]
+		res.entry.Composer, res.entry.Encoding, res.parseErr, res.err = parseComposer(data)
+	}
+
+	if err := pa.addPlugins(fsys, dir, folders, paths, results); err != nil {
+		return 0, err
+	}
+	return len(folders), nil
+}
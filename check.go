@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// runCheck prints the problems found by CheckProblems, one per line, and
+// returns the exit code: exitCycles if any problem is a cycle, exitFailure
+// for other problems and exitOK if there were none.
+func runCheck(pa *analyzer.PluginAnalyzer) int {
+	problems := pa.CheckProblems()
+	var status exitStatus
+	for _, p := range problems {
+		fmt.Printf("%s: %s\n", p.RuleID, p.Message)
+		if p.RuleID == analyzer.RuleCircularDependency {
+			status.fail(exitCycles)
+		}
+	}
+	if len(problems) > 0 {
+		status.fail(exitFailure)
+		fmt.Printf("Check failed: %d problem(s) in %d plugin(s)\n", len(problems), len(pa.InternalPluginNames()))
+		return int(status)
+	}
+	fmt.Printf("Check passed: %d plugin(s), no problems found\n", len(pa.InternalPluginNames()))
+	return exitOK
+}
+
+// gateCycles applies the cycle check of a full run to runs that don't print
+// the "Circular Dependencies" section, such as -stdout and -summary-line: if
+// there are more cycles than maxCycles (-1 tolerates none), or any with
+// assertDAG, it logs them and returns exitCycles, else exitOK.
+func gateCycles(pa *analyzer.PluginAnalyzer, maxCycles int, assertDAG bool) int {
+	cycles := pa.DetectCycles()
+	if len(cycles) == 0 || (!assertDAG && len(cycles) <= maxCycles) {
+		return exitOK
+	}
+	log.Print("Circular dependencies found:")
+	for _, cycle := range cycles {
+		log.Printf("  %s", pa.FormatCycle(cycle))
+	}
+	return exitCycles
+}
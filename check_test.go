@@ -0,0 +1,45 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// scanCyclicPlugins scans two plugins requiring each other.
+func scanCyclicPlugins(t *testing.T) *analyzer.PluginAnalyzer {
+	t.Helper()
+	pa := analyzer.NewPluginAnalyzer([]string{writePlugins(t, map[string]string{
+		"A": `{"name": "v/a", "type": "shopware-platform-plugin", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b", "type": "shopware-platform-plugin", "require": {"v/a": "*"}}`,
+	})}, false)
+	pa.Logger = log.New(io.Discard, "", 0)
+	if err := pa.ScanPlugins(); err != nil {
+		t.Fatal(err)
+	}
+	return pa
+}
+
+func TestGateCycles(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	pa := scanCyclicPlugins(t)
+	for _, tt := range []struct {
+		maxCycles int
+		assertDAG bool
+		want      int
+	}{
+		{-1, false, exitCycles},
+		{0, false, exitCycles},
+		{1, false, exitOK},
+		{1, true, exitCycles},
+	} {
+		if got := gateCycles(pa, tt.maxCycles, tt.assertDAG); got != tt.want {
+			t.Errorf("gateCycles(maxCycles=%d, assertDAG=%v) = %d, want %d", tt.maxCycles, tt.assertDAG, got, tt.want)
+		}
+	}
+}
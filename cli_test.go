@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePlugins creates a plugins directory with one folder per entry of
+// composers, holding the given composer.json content, and returns its path.
+func writePlugins(t *testing.T, composers map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for folder, composer := range composers {
+		if err := os.MkdirAll(filepath.Join(dir, folder), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, folder, "composer.json"), []byte(composer), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// runCLI runs the analyzer with args in a child process of the test binary,
+// in an empty working directory, and returns its exit code and combined
+// output.
+func runCLI(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(executable, append([]string{"-test.run=^TestCLIProcess$", "--"}, args...)...)
+	cmd.Dir = t.TempDir()
+	cmd.Env = append(os.Environ(), "PLUGIN_ANALYZER_CLI=1")
+	output, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), string(output)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exitOK, string(output)
+}
+
+// TestCLIProcess is not a real test: runCLI runs it to call main with the
+// arguments after "--".
+func TestCLIProcess(t *testing.T) {
+	if os.Getenv("PLUGIN_ANALYZER_CLI") != "1" {
+		t.Skip("only run as the analyzer command by runCLI")
+	}
+	for i, arg := range os.Args {
+		if arg == "--" {
+			os.Args = append([]string{"sw6-plugin-analyzer"}, os.Args[i+1:]...)
+			break
+		}
+	}
+	main()
+	os.Exit(exitOK)
+}
+
+func TestUnknownPluginQueriesFail(t *testing.T) {
+	dir := writePlugins(t, map[string]string{
+		"A": `{"name": "v/a", "type": "shopware-platform-plugin", "require": {"v/b": "*"}}`,
+		"B": `{"name": "v/b", "type": "shopware-platform-plugin"}`,
+	})
+	for _, flag := range []string{"-dependents", "-closure", "-explain"} {
+		code, output := runCLI(t, "-dir", dir, "-format", "mermaid", "-no-cache", flag, "B")
+		if code != exitOK {
+			t.Errorf("%s B: exit status %d, want %d:\n%s", flag, code, exitOK, output)
+		}
+		code, output = runCLI(t, "-dir", dir, "-format", "mermaid", "-no-cache", flag, "Typo")
+		if code != exitUsage || !strings.Contains(output, `unknown plugin "Typo"`) {
+			t.Errorf("%s Typo: exit status %d, want %d with the unknown plugin named:\n%s", flag, code, exitUsage, output)
+		}
+	}
+}
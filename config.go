@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are the config files looked for in the working directory
+// when -config is not given, in order of preference.
+var configFileNames = []string{".plugin-analyzer.yaml", ".plugin-analyzer.yml", ".plugin-analyzer.json"}
+
+// Config holds default option values from a config file, keyed by flag
+// name without the leading dash. Repeatable flags may have several values.
+type Config struct {
+	Path    string
+	Options map[string][]string
+}
+
+// LoadConfig reads a config file mapping flag names to values, e.g.
+//
+//	dir: [custom/plugins, custom/static-plugins]
+//	format: mermaid,graphviz
+//	show-external: true
+//
+// YAML and JSON are both accepted. Values are scalars or, for repeatable
+// flags, lists of scalars.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	// JSON is valid YAML, so one parser handles both.
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	config := &Config{Path: path, Options: make(map[string][]string)}
+	for name, value := range raw {
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				if !isConfigScalar(item) {
+					return nil, fmt.Errorf("invalid value for %q in %s: lists may only hold scalars", name, path)
+				}
+				config.Options[name] = append(config.Options[name], fmt.Sprint(item))
+			}
+		case nil:
+			config.Options[name] = []string{""}
+		default:
+			if !isConfigScalar(v) {
+				return nil, fmt.Errorf("invalid value for %q in %s: expected a scalar or a list", name, path)
+			}
+			config.Options[name] = []string{fmt.Sprint(v)}
+		}
+	}
+	return config, nil
+}
+
+func isConfigScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, float64:
+		return true
+	}
+	return false
+}
+
+// findConfigFile returns the first of configFileNames present in the
+// working directory, or "" if there is none.
+func findConfigFile() string {
+	for _, name := range configFileNames {
+		if info, err := os.Stat(name); err == nil && !info.IsDir() {
+			return name
+		}
+	}
+	return ""
+}
+
+// Apply sets every configured option on fs that was not given on the
+// command line, so flags override the config file, which overrides the
+// built-in defaults. fs must already be parsed.
+func (c *Config) Apply(fs *flag.FlagSet) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+	names := make([]string, 0, len(c.Options))
+	for name := range c.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "config" || fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown option %q in %s", name, c.Path)
+		}
+		if given[name] {
+			continue
+		}
+		for _, value := range c.Options[name] {
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("invalid value %q for %q in %s: %w", value, name, c.Path, err)
+			}
+		}
+	}
+	return nil
+}
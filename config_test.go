@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	for _, tt := range []struct {
+		name, file, content string
+	}{
+		{"yaml", ".plugin-analyzer.yaml", "format: mermaid\nshow-external: true\ndir: [a, b]\n"},
+		{"json", ".plugin-analyzer.json", `{"format": "mermaid", "show-external": true, "dir": ["a", "b"]}`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := LoadConfig(writeConfig(t, tt.file, tt.content))
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			format := fs.String("format", "both", "")
+			output := fs.String("output", "output", "")
+			showExternal := fs.Bool("show-external", false, "")
+			var dirs stringListFlag
+			fs.Var(&dirs, "dir", "")
+			if err := fs.Parse([]string{"-format", "json"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := config.Apply(fs); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+
+			if *format != "json" {
+				t.Errorf("format = %q, want the flag's json", *format)
+			}
+			if !*showExternal {
+				t.Error("show-external not taken from the config file")
+			}
+			if want := (stringListFlag{"a", "b"}); !reflect.DeepEqual(dirs, want) {
+				t.Errorf("dir = %q, want %q from the config file", dirs, want)
+			}
+			if *output != "output" {
+				t.Errorf("output = %q, want the default", *output)
+			}
+		})
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("workers", 1, "")
+	fs.Parse(nil)
+	for _, content := range []string{"unknown: 1\n", "workers: many\n", "config: other.yaml\n"} {
+		config, err := LoadConfig(writeConfig(t, "c.yaml", content))
+		if err != nil {
+			t.Fatalf("LoadConfig(%q): %v", content, err)
+		}
+		if err := config.Apply(fs); err == nil {
+			t.Errorf("Apply(%q) succeeded, want an error", content)
+		}
+	}
+	if _, err := LoadConfig(writeConfig(t, "c.yaml", "dir: [{a: b}]\n")); err == nil {
+		t.Error("LoadConfig accepted a list of maps")
+	}
+}
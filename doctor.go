@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// graphvizEngines are the layout programs of a standard Graphviz install.
+var graphvizEngines = []string{"dot", "neato", "fdp", "sfdp", "circo", "twopi"}
+
+// doctorCheck is one line of the doctor checklist.
+type doctorCheck struct {
+	Status string // PASS, WARN or FAIL
+	Text   string
+}
+
+// runDoctor implements the "doctor" subcommand: it checks the environment
+// and the plugins directory and prints a pass/fail checklist. It returns
+// the process exit code, 1 if any check failed.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	pluginsDir := fs.String("dir", "", "Directory containing plugin folders")
+	fs.Parse(args)
+
+	var checks []doctorCheck
+	add := func(status, format string, a ...interface{}) {
+		checks = append(checks, doctorCheck{Status: status, Text: fmt.Sprintf(format, a...)})
+	}
+
+	if _, err := exec.LookPath("dot"); err != nil {
+		add("FAIL", "Graphviz is not installed (dot not found in PATH)")
+	} else {
+		// dot -V prints its version to stderr.
+		out, _ := exec.Command("dot", "-V").CombinedOutput()
+		add("PASS", "Graphviz is installed: %s", strings.TrimSpace(string(out)))
+	}
+	var engines []string
+	for _, engine := range graphvizEngines {
+		if _, err := exec.LookPath(engine); err == nil {
+			engines = append(engines, engine)
+		}
+	}
+	if len(engines) == 0 {
+		add("WARN", "No Graphviz layout engines found")
+	} else {
+		add("PASS", "Graphviz engines available: %s", strings.Join(engines, ", "))
+	}
+
+	if *pluginsDir == "" {
+		add("WARN", "No -dir given, plugins directory not checked")
+	} else {
+		checkPluginsDir(*pluginsDir, add)
+	}
+
+	failed := false
+	for _, c := range checks {
+		fmt.Printf("[%s] %s\n", c.Status, c.Text)
+		failed = failed || c.Status == "FAIL"
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// checkPluginsDir checks that dir is readable and holds parsable plugins.
+func checkPluginsDir(dir string, add func(status, format string, a ...interface{})) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		add("FAIL", "Plugins directory %s is not readable: %v", dir, err)
+		return
+	}
+	add("PASS", "Plugins directory %s is readable", dir)
+
+	folders, found, parsed := 0, 0, 0
+	var broken []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		folders++
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), "composer.json"))
+		if err != nil {
+			continue
+		}
+		found++
+		if data, _, err = analyzer.ToUTF8(data); err == nil {
+			var composer analyzer.ComposerJSON
+			err = json.Unmarshal(data, &composer)
+			if err == nil && composer.Name == "" {
+				err = fmt.Errorf("no name")
+			}
+		}
+		if err != nil {
+			broken = append(broken, entry.Name())
+			continue
+		}
+		parsed++
+	}
+
+	switch {
+	case folders == 0:
+		add("FAIL", "No plugin folders found in %s", dir)
+		return
+	case found == 0:
+		add("FAIL", "None of the %d folders contains a composer.json; is -dir pointing at custom/plugins?", folders)
+		return
+	case found < folders:
+		add("WARN", "%d of %d folders contain a composer.json", found, folders)
+	default:
+		add("PASS", "All %d folders contain a composer.json", folders)
+	}
+
+	switch {
+	case parsed == 0:
+		add("FAIL", "None of the %d composer.json files could be parsed", found)
+	case len(broken) > 0:
+		add("WARN", "%d composer.json file(s) could not be parsed: %s", len(broken), strings.Join(broken, ", "))
+	default:
+		add("PASS", "All %d composer.json files parse", found)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Exit codes of a run. Scripts can rely on them, so they are listed in
+// -help and only ever extended.
+const (
+	exitOK         = 0
+	exitFailure    = 1 // a report found problems or an operation failed
+	exitScanErrors = 2 // -strict and plugin folders were skipped or plugin names or classes collide
+	exitCycles     = 3 // circular dependencies between internal plugins
+	exitNoGraphviz = 4 // a requested output needs Graphviz, which is not installed
+	exitUsage      = 5 // invalid flags, config file, input files or plugins directories named by flags
+)
+
+const exitCodeHelp = `
+Exit codes:
+  0  success
+  1  a report found problems (denied packages, forbidden dependents, rule
+     or manifest violations, unexpected or missing -assert-edges edges,
+     exceeded -max-edges, -max-cycles or -max-depth-allowed budgets, -check
+     problems other than cycles, with -strict disallowed external packages)
+     or an operation failed
+  2  -strict and plugin folders were skipped or plugin names or classes
+     collide
+  3  circular dependencies were found (more than -max-cycles, if set,
+     unless -assert-dag)
+  4  Graphviz is needed for a requested output but not installed
+  5  invalid flags, config file or input files named by flags, plugins
+     directories that are missing, not directories or hold no plugins, or
+     a -dependents, -closure or -explain plugin that wasn't found
+When several apply, the first one detected is used. All requested outputs
+are written before exiting, unless the flags or plugins directories are
+invalid.
+`
+
+// printUsage is flag.Usage: the flag defaults followed by the exit codes.
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprint(out, exitCodeHelp)
+}
+
+// usageFatal logs v and exits with exitUsage.
+func usageFatal(v ...interface{}) {
+	log.Print(v...)
+	os.Exit(exitUsage)
+}
+
+// usageFatalf logs a formatted message and exits with exitUsage.
+func usageFatalf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+	os.Exit(exitUsage)
+}
+
+// exitStatus is the exit code a run ends with. The first failure recorded
+// wins, so later reports don't mask the cause scripts usually care about.
+type exitStatus int
+
+// fail records code unless an earlier failure was recorded.
+func (s *exitStatus) fail(code int) {
+	if *s == exitOK {
+		*s = exitStatus(code)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import "strings"
+
+// stringListFlag collects the values of a flag that may be repeated.
+// Each occurrence may also hold a comma-separated list.
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
+	}
+	return nil
+}
+
+// repeatedFlag collects the values of a flag that may be repeated, keeping
+// each occurrence whole, for values that may themselves contain commas.
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	if value = strings.TrimSpace(value); value != "" {
+		*f = append(*f, value)
+	}
+	return nil
+}
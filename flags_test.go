@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+func TestDOTAttributeFlagsKeepCommas(t *testing.T) {
+	var nodeAttrs repeatedFlag
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&nodeAttrs, "node-attr", "")
+	if err := fs.Parse([]string{"-node-attr", "style=rounded,filled", "-node-attr", "fontname=Arial"}); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs, err := analyzer.ParseDOTAttributes("node-attr", nodeAttrs)
+	if err != nil {
+		t.Fatalf("ParseDOTAttributes: %v", err)
+	}
+	want := []analyzer.DOTAttribute{{Key: "style", Value: "rounded,filled"}, {Key: "fontname", Value: "Arial"}}
+	if !reflect.DeepEqual(attrs, want) {
+		t.Errorf("ParseDOTAttributes() = %v, want %v", attrs, want)
+	}
+}
@@ -1,3 +1,12 @@
 module github.com/topdata-software-gmbh/sw6-plugin-analyzer
 
 go 1.23.1
+
+require google.golang.org/protobuf v1.36.12
+
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.13.0 // indirect
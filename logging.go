@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// Diagnostics follow the analyzer package's LogLevel, set by -quiet and
+// -verbose. Results such as the summary and -stdout output always go to
+// stdout.
+
+// warnf logs a warning unless running with -quiet.
+func warnf(format string, args ...interface{}) {
+	if analyzer.LogLevel >= analyzer.Normal {
+		log.Printf("Warning: "+format, args...)
+	}
+}
+
+// progressf reports progress, such as a written file, on stderr unless
+// running with -quiet.
+func progressf(format string, args ...interface{}) {
+	if analyzer.LogLevel >= analyzer.Normal {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// logGenerateFailure logs that generating what failed. The diagnostics of a
+// failed Graphviz run follow on their own indented lines, as dot prints
+// one per problem.
+func logGenerateFailure(what string, err error) {
+	var gvErr *analyzer.GraphvizError
+	if !errors.As(err, &gvErr) || gvErr.Stderr == "" {
+		log.Printf("Failed to generate %s: %v", what, err)
+		return
+	}
+	log.Printf("Failed to generate %s: %s exited with status %d:\n    %s",
+		what, gvErr.Engine, gvErr.ExitCode, strings.ReplaceAll(gvErr.Stderr, "\n", "\n    "))
+}
@@ -1,268 +1,1540 @@
 package main
 
 import (
-	"encoding/json"
+	"archive/zip"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
 )
 
-type ComposerJSON struct {
-	Name    string            `json:"name"`
-	Require map[string]string `json:"require"`
+// outputFormats lists the values accepted by -format, besides "both".
+var outputFormats = []string{"mermaid", "graphviz", "html", "html-interactive", "ascii", "cypher", "dgml", "graphml", "json", "jsonl", "pdf-report", "structurizr", "markdown", "plantuml", "csv"}
+
+// parseFormats turns a comma-separated -format value into a set of formats.
+// "both" is shorthand for mermaid and graphviz.
+func parseFormats(value string) (map[string]bool, error) {
+	formats := make(map[string]bool)
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		if f == "both" {
+			formats["mermaid"] = true
+			formats["graphviz"] = true
+			continue
+		}
+		known := false
+		for _, name := range outputFormats {
+			if f == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown output format %q (expected %s or both)", f, strings.Join(outputFormats, ", "))
+		}
+		formats[f] = true
+	}
+	return formats, nil
 }
 
-type Plugin struct {
-	Name         string
-	FolderName   string
-	Dependencies []string
-	IsExternal   bool
+// writeJSONL streams the JSON Lines graph into the file at path and reports
+// the result.
+func writeJSONL(pa *analyzer.PluginAnalyzer, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pa.StreamJSONL(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	progressf("JSON Lines graph saved to %s\n", path)
+	return nil
 }
 
-type PluginAnalyzer struct {
-	PluginsDir        string
-	Plugins           map[string]*Plugin
-	ShowExternalDeps  bool
-	ExternalDepsCount map[string]int
+// writeOutputFile writes generated content to path and reports the result;
+// a failed write fails the run with exitFailure.
+func writeOutputFile(status *exitStatus, path string, content []byte, description string) {
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		log.Printf("Failed to write %s: %v", description, err)
+		status.fail(exitFailure)
+		return
+	}
+	progressf("%s saved to %s\n", description, path)
 }
 
-func NewPluginAnalyzer(dir string, showExternal bool) *PluginAnalyzer {
-	return &PluginAnalyzer{
-		PluginsDir:        dir,
-		Plugins:           make(map[string]*Plugin),
-		ShowExternalDeps:  showExternal,
-		ExternalDepsCount: make(map[string]int),
+// writeScript writes an executable shell script and reports the result like
+// writeOutputFile.
+func writeScript(status *exitStatus, path, content, description string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0755); err != nil {
+		log.Printf("Failed to write %s: %v", description, err)
+		status.fail(exitFailure)
+		return
 	}
+	progressf("%s saved to %s\n", description, path)
 }
 
-func (pa *PluginAnalyzer) ScanPlugins() error {
-	entries, err := os.ReadDir(pa.PluginsDir)
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(runDoctor(os.Args[2:]))
+	}
+
+	var pluginsDirs stringListFlag
+	flag.Var(&pluginsDirs, "dir", "Directory containing plugin folders (repeatable, e.g. custom/plugins and custom/static-plugins)")
+	recursive := flag.Bool("recursive", false, "Also find plugins nested in subdirectories of -dir, e.g. custom/plugins/Bundles/MyPlugin")
+	zipFile := flag.String("zip", "", "Scan the plugin folders in this zip archive of custom/plugins instead of -dir")
+	vendorDir := flag.String("vendor-dir", "", "Scan the Shopware plugins installed in this composer vendor/ directory instead of -dir")
+	outputFormat := flag.String("format", "both", "Output formats, comma-separated: mermaid, graphviz, html, html-interactive, ascii, cypher, dgml, graphml, json, jsonl, pdf-report, structurizr, markdown, plantuml, csv, or both")
+	outputDir := flag.String("output", "output", "Output directory for generated files")
+	baseName := flag.String("name", "dependencies", "Base name of the generated graph files, e.g. <name>.svg and <name>.json")
+	showExternal := flag.Bool("show-external", false, "Include external dependencies in the graph")
+	includeDev := flag.Bool("include-dev", false, "Include require-dev dependencies as dashed edges")
+	devOptional := flag.Bool("dev-optional", true, "Mark require-dev edges as optional (suggest edges always are)")
+	showSuggest := flag.Bool("show-suggest", false, "Include suggest entries as dotted edges")
+	includeBundles := flag.Bool("include-bundles", false, "Include extra.shopware-bundles references to scanned plugins as bold edges")
+	showConflict := flag.Bool("show-conflict", false, "Draw conflict entries between plugins as red dashed edges")
+	noCycleHighlight := flag.Bool("no-cycle-highlight", false, "Don't color plugins on a dependency cycle red in the Mermaid graph")
+	var collapseVendors stringListFlag
+	flag.Var(&collapseVendors, "collapse-vendor", "Draw all plugins of this vendor as one aggregate node with their edges rerouted to it (repeatable)")
+	collapseChains := flag.Bool("collapse-chains", false, "Draw chains of plugins with one dependent and one dependency as a single edge labeled with the hidden count")
+	hideAboveFanout := flag.Int("hide-above-fanout", 0, "Leave plugins depending on more than this many nodes out of the rendered graphs (0 disables)")
+	hubStubs := flag.Bool("hub-stubs", false, "With -hide-above-fanout, keep hidden hubs as labeled stub nodes without outgoing edges")
+	maxDepth := flag.Int("max-depth", -1, "Leave plugins more than this many levels below a root out of the rendered graphs (-1 is unlimited)")
+	maxExternal := flag.Int("max-external", -1, "With -show-external, draw only this many most-used external packages and merge the rest into one \"other externals\" node (-1 is unlimited)")
+	depthPlaceholders := flag.Bool("max-depth-placeholders", false, "With -max-depth, draw a \"...\" node below each plugin whose dependencies were cut off")
+	mergeEdges := flag.Bool("merge-edges", false, "Merge parallel edges of different kinds between the same pair of nodes")
+	engine := flag.String("engine", "dot", "Graphviz layout engine: dot, neato, fdp, sfdp or circo")
+	imageFormat := flag.String("image-format", "svg", "Image format of the graphviz output: svg, png or pdf")
+	validateSVG := flag.Bool("validate-svg", false, "Check that the generated SVG is well-formed XML with an <svg> root")
+	constrainInternalOnly := flag.Bool("constraint-internal-only", false, "Rank Graphviz nodes by internal edges only; edges to external packages get constraint=false")
+	mergeMutual := flag.Bool("merge-mutual", false, "Draw plugins requiring each other with one red double-headed Graphviz edge")
+	clusterBy := flag.String("cluster-by", "", "Group Graphviz nodes into clusters: vendor or meta:<field>")
+	clusterByVendor := flag.Bool("cluster-by-vendor", false, "Group Graphviz nodes into one cluster per composer vendor; short for -cluster-by vendor")
+	sarifPath := flag.String("sarif", "", "Write cycles, conflicts and missing internal dependencies as SARIF to this file")
+	githubAnnotations := flag.Bool("github", false, "Also print skipped folders, cycles and policy violations as GitHub Actions annotations")
+	var internalPrefixes stringListFlag
+	flag.Var(&internalPrefixes, "internal-prefix", "Vendor prefix of internal packages, e.g. topdata/ (repeatable)")
+	var graphAttrs, nodeAttrs, edgeAttrs repeatedFlag
+	flag.Var(&graphAttrs, "graph-attr", "Graphviz graph attribute key=value, e.g. splines=ortho (repeatable)")
+	flag.Var(&nodeAttrs, "node-attr", "Graphviz default node attribute key=value, e.g. fontname=Arial (repeatable)")
+	flag.Var(&edgeAttrs, "edge-attr", "Graphviz default edge attribute key=value, e.g. arrowsize=0.5 (repeatable)")
+	cssPath := flag.String("css", "", "Custom CSS file injected into the HTML report")
+	themePath := flag.String("theme", "", "JSON file with the node fill colors, edge colors and node shape of the Graphviz output")
+	checkUpdates := flag.Bool("check-updates", false, "Query packagist.org and flag external dependencies whose latest release is excluded by a constraint")
+	updateTimeout := flag.Duration("update-timeout", 10*time.Second, "HTTP timeout for each packagist request made by -check-updates")
+	strict := flag.Bool("strict", false, "Exit with status 2 if any plugin folder was skipped because its composer.json is missing or unreadable, or if plugin names or classes collide; with -allowed-externals, status 1 on disallowed external packages")
+	failFast := flag.Bool("fail-fast", false, "Stop scanning at the first unreadable or malformed composer.json")
+	protobufPath := flag.String("protobuf", "", "Write the dependency graph as a protobuf PluginGraph message to this file")
+	title := flag.String("title", "", "Title shown in the generated graphs together with the generation date")
+	splitJSON := flag.Bool("split-json", false, "Write one JSON detail file per plugin to <output>/plugins/")
+	includePlatform := flag.Bool("include-platform", false, "Include platform packages like php, ext-* and composer-plugin-api in graphs and reports")
+	var ignoredPrefixes stringListFlag
+	flag.Var(&ignoredPrefixes, "ignore-external-prefix", "Drop external requirements with this prefix from graphs and counts, e.g. symfony/ (repeatable); platform packages are dropped unless -include-platform")
+	otelPath := flag.String("otel-attributes", "", "Write plugin names and versions as OpenTelemetry resource attributes (flat JSON) to this file")
+	var aliasGroups stringListFlag
+	flag.Var(&aliasGroups, "alias-group", "Treat an external package as an alias of another: alias=canonical (repeatable)")
+	compareLock := flag.Bool("compare-lock", false, "Report packages whose version in a plugin's composer.lock violates its composer.json constraint")
+	iciclePath := flag.String("icicle", "", "Write an icicle SVG of transitive dependency sizes to this file")
+	var icicleRoots stringListFlag
+	flag.Var(&icicleRoots, "icicle-root", "Plugin the icicle diagram starts from (repeatable, default: all plugins without dependents)")
+	var forcedExternal stringListFlag
+	flag.Var(&forcedExternal, "external-prefix-force", "Treat packages with this prefix as external even if their folder is scanned (repeatable)")
+	var pluginTypes stringListFlag
+	flag.Var(&pluginTypes, "plugin-type", "Composer type of plugins (repeatable, default "+analyzer.DefaultPluginType+"); folders of other types are libraries")
+	showLibraries := flag.Bool("show-libraries", false, "Draw folders whose composer type is not a -plugin-type as library nodes instead of external packages")
+	var excludePatterns, includePatterns stringListFlag
+	ignoreCase := flag.Bool("ignore-case", false, "Match folder names given to flags and -exclude/-include globs case-insensitively, as Windows file systems do")
+	flag.Var(&excludePatterns, "exclude", "Leave out plugins whose folder or composer name matches this glob, e.g. Test* (repeatable)")
+	flag.Var(&includePatterns, "include", "Only scan plugins whose folder or composer name matches this glob; -exclude wins (repeatable)")
+	var onlyTypes stringListFlag
+	flag.Var(&onlyTypes, "only-types", "Only include plugins of these composer types, e.g. shopware-platform-plugin (repeatable)")
+	rootPlugin := flag.String("root", "", "Limit all outputs to this plugin and everything it transitively depends on")
+	focus := flag.String("focus", "", "Limit all outputs to this plugin and its neighbors within -depth hops in either direction")
+	interactive := flag.Bool("interactive", false, "After scanning, pick the plugins to focus on from a numbered list; needs a terminal")
+	focusDepth := flag.Int("depth", 1, "Hops around the -focus or -interactive plugins to include; 0 shows only the plugin itself")
+	vendorScope := flag.String("vendor", "", "Limit all outputs to this vendor's plugins plus one hop in each direction")
+	externalProximity := flag.Int("external-proximity", 0, "With -show-external, limit all outputs to internal plugins within this many hops of an external dependency")
+	denylistPath := flag.String("denylist", "", "File listing forbidden packages (one name or glob per line); exit non-zero if any plugin requires one")
+	allowedExternalsPath := flag.String("allowed-externals", "", "File listing permitted external packages (one name or glob per line); report any other external dependency, and with -strict exit non-zero")
+	upload := flag.String("upload", "", "Share the Mermaid graph and print its URL: \"mermaid.live\" for an editor link, or a paste endpoint URL to POST to")
+	uploadToken := flag.String("upload-token", "", "Bearer token for the -upload endpoint (default $"+uploadTokenEnv+")")
+	uploadInsecure := flag.Bool("upload-insecure", false, "Send the -upload token even if the endpoint is not HTTPS")
+	var adrFocus stringListFlag
+	flag.Var(&adrFocus, "adr", "Write a Markdown snapshot of this plugin's dependencies for an ADR to <output>/adr-snapshot.md (repeatable)")
+	projectPath := flag.String("project", "", "Project composer.json; limit all outputs to the scanned plugins it requires and everything they depend on")
+	rootComposerPath := flag.String("root-composer", "", "Project root composer.json whose conflict and replace sections apply to all plugins")
+	manifestPath := flag.String("verify", "", "YAML manifest of declared (and forbidden) internal dependencies; exit non-zero if the graph deviates")
+	var forbidDependents stringListFlag
+	flag.Var(&forbidDependents, "forbid-dependents", "Plugin (folder or composer name) no other plugin may depend on; exit non-zero if one does (repeatable)")
+	rulesPath := flag.String("rules", "", "File of forbidden edges, one per line like: deny: \"*-core\" -> \"*-ui\"; exit non-zero if any edge violates one")
+	asciiMaxNodes := flag.Int("ascii-max-nodes", 20, "Largest graph the ascii format draws as boxes; bigger graphs are printed as an edge list")
+	typoDistance := flag.Int("typo-distance", 2, "Report external requirements within this edit distance of an internal plugin name as possible typos (0 disables)")
+	timing := flag.Bool("timing", false, "Print how long scanning, analysis and each generator took to stderr")
+	cpuProfile := flag.String("cpuprofile", "", "Write a pprof CPU profile of the run to this file")
+	maxEdges := flag.Int("max-edges", -1, "Exit non-zero if the graph has more edges than this (-1 is unchecked)")
+	maxCycles := flag.Int("max-cycles", -1, "Exit non-zero only if there are more circular dependencies than this (-1 fails on any cycle)")
+	maxDepthAllowed := flag.Int("max-depth-allowed", -1, "Exit non-zero if the longest dependency chain is deeper than this (-1 is unchecked)")
+	statsJSON := flag.String("stats-json", "", "Write the graph statistics as JSON to this file")
+	bomPath := flag.String("bom", "", "Write a bill of materials of all external packages to this file: CSV for a .csv name, CycloneDX JSON otherwise")
+	bomLock := flag.String("bom-lock", "", "composer.lock whose versions are listed as resolved versions in the -bom output (default -lock)")
+	lockPath := flag.String("lock", "", "Project composer.lock whose locked versions label the external nodes and whose abandoned packages are reported")
+	externalCounts := flag.String("external-counts", "", "Write the usage count of each external package as JSON to this file, for use as an -external-delta baseline")
+	externalDelta := flag.String("external-delta", "", "Report only external packages whose usage count differs from this baseline JSON file")
+	assertEdges := flag.String("assert-edges", "", "File of the expected edges between internal plugins, one per line like: PluginA -> PluginB; exit non-zero if the graph differs")
+	compare := flag.String("compare", "", "JSON graph of an earlier scan (-format json); print the plugins and edges added and removed since")
+	dependentsOf := flag.String("dependents", "", "List the plugins that require this plugin (folder or composer name)")
+	transitive := flag.Bool("transitive", false, "With -dependents, also list plugins requiring it through other plugins")
+	closureOf := flag.String("closure", "", "List every internal plugin this plugin (folder or composer name) pulls in, directly or transitively")
+	explain := flag.String("explain", "", "Describe this plugin's dependencies, dependents and the cycles it takes part in")
+	printOrder := flag.Bool("order", false, "Print the internal plugins in install order, dependencies first, one per line")
+	planFrom := flag.String("plan-from", "", "Plugins directory of the currently deployed state; print the ordered uninstall, install and update operations leading to the scanned state")
+	installScript := flag.String("gen-install-script", "", "Write a shell script installing the plugins in dependency order to this file, plus an uninstall script in reverse order")
+	showAll := flag.Bool("show-all", false, "Also list plugins without any dependencies in the summary")
+	tree := flag.Bool("tree", false, "Print the transitive internal dependencies of every root plugin as a tree")
+	checkUnused := flag.Bool("check-unused-deps", false, "Report internal dependencies whose PSR-4 namespace the depending plugin's PHP and XML files never mention")
+	roots := flag.Bool("roots", false, "List internal plugins no other internal plugin depends on")
+	leaves := flag.Bool("leaves", false, "List internal plugins without internal dependencies")
+	metricsIncludeExternal := flag.Bool("metrics-include-external", false, "Count edges to external packages (with -show-external) in fan-in/fan-out metrics")
+	assertDAG := flag.Bool("assert-dag", false, "Exit with status 3 if the internal dependency graph has any circular dependency, regardless of -max-cycles, listing the cycles")
+	summaryLine := flag.Bool("summary-line", false, "Only print the key figures as one line of key=value pairs, e.g. plugins=42 external=17 edges=88 cycles=0 orphans=3; no files are written")
+	check := flag.Bool("check", false, "Only check for cycles, version conflicts and missing internal plugins, print them and exit non-zero if there are any; no files are written")
+	stdout := flag.Bool("stdout", false, "Write the mermaid or json output to stdout instead of files and skip the summary")
+	quiet := flag.Bool("quiet", false, "Only report errors and the requested output; suppress warnings and progress messages")
+	verbose := flag.Bool("verbose", false, "Log every composer.json read and every dependency edge added to stderr")
+	workers := flag.Int("workers", runtime.NumCPU(), "Number of composer.json files read in parallel")
+	noCache := flag.Bool("no-cache", false, "Parse every composer.json instead of reusing unchanged ones from "+analyzer.DefaultCacheFile)
+	serve := flag.String("serve", "", "Serve the HTML report on this address, e.g. :8080, and the JSON graph at /graph.json, rescanning on every request")
+	watch := flag.Bool("watch", false, "Generate the outputs, then watch the plugins directories and generate them again whenever a composer.json changes, until interrupted")
+	watchServe := flag.String("watch-serve", "", "Serve a live graph on this address, e.g. :8080, rescanning when composer.json files change")
+	couplingTable := flag.Bool("coupling-table", false, "Print direct and transitive dependency and dependent counts of every plugin as a table")
+	sortBy := flag.String("sort-by", "transitive-deps", "Column to sort the -coupling-table by: "+strings.Join(analyzer.CouplingColumns, ", "))
+	linkTemplate := flag.String("link-template", "", "Make Graphviz nodes links to this URL, e.g. https://wiki/plugins/{folder}; {folder} and {name} are replaced per plugin")
+	colorByConstraint := flag.Bool("color-by-constraint", false, "Color internal Graphviz edges by their version constraint: exact pins red, ranges such as ^1.2 amber, * and other open constraints green")
+	colorByDepth := flag.Bool("color-by-depth", false, "Fill Graphviz nodes with a color gradient by their longest distance from a plugin nothing depends on")
+	minShopware := flag.String("min-shopware", "", "List plugins whose shopware/core constraint excludes this Shopware version, e.g. 6.6.0")
+	pathFlag := flag.String("path", "", "Print the shortest dependency path between two plugins given as from:to and highlight it in the Graphviz output; exit non-zero if there is none")
+	var changed stringListFlag
+	flag.Var(&changed, "changed", "Plugins changed by a PR, by folder or composer name; highlighted with their edges in the Graphviz output while the rest is dimmed (repeatable)")
+	couplingReport := flag.Bool("coupling-report", false, "Print the fan-in and fan-out of every plugin, most depended-upon first")
+	hotspots := flag.Bool("hotspots", false, "Report coupling hotspots exceeding both fan-in and fan-out thresholds")
+	hotspotFanIn := flag.Int("hotspot-fan-in", 3, "Fan-in a plugin must exceed to be reported as a coupling hotspot")
+	hotspotFanOut := flag.Int("hotspot-fan-out", 3, "Fan-out a plugin must exceed to be reported as a coupling hotspot")
+	configPath := flag.String("config", "", "Config file with default option values (default: .plugin-analyzer.yaml, .yml or .json in the working directory)")
+	showVersion := flag.Bool("version", false, "Print the version, git commit and build date and exit")
+	flag.Usage = printUsage
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(exitOK)
+		}
+		os.Exit(exitUsage)
+	}
+
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+
+	if *configPath == "" {
+		*configPath = findConfigFile()
+	}
+	if *configPath != "" {
+		config, err := LoadConfig(*configPath)
+		if err != nil {
+			usageFatal(err)
+		}
+		if err := config.Apply(flag.CommandLine); err != nil {
+			usageFatal(err)
+		}
+	}
+
+	if *quiet && *verbose {
+		usageFatal("-quiet and -verbose cannot be combined")
+	}
+	if *quiet {
+		analyzer.LogLevel = analyzer.Quiet
+	} else if *verbose {
+		analyzer.LogLevel = analyzer.Verbose
+	}
+
+	var timer *phaseTimer
+	if *timing {
+		timer = newPhaseTimer()
+	}
+	stopProfile := noop
+	if *cpuProfile != "" {
+		stop, err := startCPUProfile(*cpuProfile)
+		if err != nil {
+			usageFatalf("Failed to start -cpuprofile: %v", err)
+		}
+		stopProfile = stop
+	}
+
+	if len(pluginsDirs) == 0 && *vendorDir == "" && *zipFile == "" {
+		usageFatal("Please specify plugins directory with -dir flag")
+	}
+	if len(pluginsDirs) > 0 && *vendorDir != "" {
+		usageFatal("-dir and -vendor-dir cannot be combined")
+	}
+	if *zipFile != "" && (len(pluginsDirs) > 0 || *vendorDir != "") {
+		usageFatal("-zip cannot be combined with -dir or -vendor-dir")
+	}
+	if *interactive && *focus != "" {
+		usageFatal("-interactive and -focus cannot be combined")
+	}
+	if *zipFile != "" && (*recursive || *watchServe != "" || *watch) {
+		usageFatal("-zip cannot be combined with -recursive, -watch or -watch-serve")
+	}
+	if *watch && (*watchServe != "" || *serve != "" || *interactive || *stdout) {
+		usageFatal("-watch cannot be combined with -watch-serve, -serve, -interactive or -stdout")
+	}
+	if *baseName == "" || strings.ContainsAny(*baseName, `/\`) {
+		usageFatalf("Invalid -name %q: expected a file name without directory", *baseName)
+	}
+	if *recursive && *vendorDir != "" {
+		usageFatal("-recursive and -vendor-dir cannot be combined")
+	}
+
+	if *externalProximity > 0 && !*showExternal {
+		usageFatal("-external-proximity requires -show-external")
+	}
+	if *maxDepth < -1 {
+		usageFatalf("Invalid -max-depth %d: expected -1 (unlimited) or a depth of 0 or more", *maxDepth)
+	}
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{{"max-edges", *maxEdges}, {"max-cycles", *maxCycles}, {"max-depth-allowed", *maxDepthAllowed}} {
+		if limit.value < -1 {
+			usageFatalf("Invalid -%s %d: expected -1 (unchecked) or a limit of 0 or more", limit.name, limit.value)
+		}
+	}
+	if *maxExternal < -1 {
+		usageFatalf("Invalid -max-external %d: expected -1 (unlimited) or a count of 0 or more", *maxExternal)
+	}
+
+	if !analyzer.ImageFormats[*imageFormat] {
+		usageFatalf("Unsupported -image-format %q (expected svg, png or pdf)", *imageFormat)
+	}
+
+	formats, err := parseFormats(*outputFormat)
 	if err != nil {
-		return fmt.Errorf("failed to read plugins directory: %w", err)
+		usageFatal(err)
 	}
 
-	// First pass: collect all internal plugins
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+	if *clusterByVendor {
+		if *clusterBy != "" && *clusterBy != "vendor" {
+			usageFatalf("-cluster-by-vendor and -cluster-by %q cannot be combined", *clusterBy)
+		}
+		*clusterBy = "vendor"
+	}
+	if err := analyzer.ParseClusterBy(*clusterBy); err != nil {
+		usageFatal(err)
+	}
+
+	aliases, err := analyzer.ParseAliasGroups(aliasGroups)
+	if err != nil {
+		usageFatal(err)
+	}
+
+	var denylist []string
+	if *denylistPath != "" {
+		denylist, err = analyzer.LoadDenylist(*denylistPath)
+		if err != nil {
+			usageFatal(err)
 		}
+	}
 
-		composerPath := filepath.Join(pa.PluginsDir, entry.Name(), "composer.json")
-		if _, err := os.Stat(composerPath); os.IsNotExist(err) {
-			log.Printf("Warning: No composer.json found in %s", entry.Name())
-			continue
+	var allowedExternals []string
+	if *allowedExternalsPath != "" {
+		allowedExternals, err = analyzer.LoadAllowlist(*allowedExternalsPath)
+		if err != nil {
+			usageFatal(err)
 		}
+	}
 
-		composerData, err := ioutil.ReadFile(composerPath)
+	var rules []analyzer.DependencyRule
+	if *rulesPath != "" {
+		rules, err = analyzer.LoadRules(*rulesPath)
 		if err != nil {
-			log.Printf("Error reading composer.json in %s: %v", entry.Name(), err)
-			continue
+			usageFatal(err)
 		}
+	}
 
-		var composer ComposerJSON
-		if err := json.Unmarshal(composerData, &composer); err != nil {
-			log.Printf("Error parsing composer.json in %s: %v", entry.Name(), err)
-			continue
+	var expectedEdges []analyzer.ExpectedEdge
+	if *assertEdges != "" {
+		expectedEdges, err = analyzer.LoadExpectedEdges(*assertEdges)
+		if err != nil {
+			usageFatal(err)
 		}
+	}
 
-		pa.Plugins[composer.Name] = &Plugin{
-			Name:       composer.Name,
-			FolderName: entry.Name(),
-			IsExternal: false,
-		}
-	}
-
-	// Second pass: collect dependencies
-	for _, plugin := range pa.Plugins {
-		composerPath := filepath.Join(pa.PluginsDir, plugin.FolderName, "composer.json")
-		composerData, _ := ioutil.ReadFile(composerPath)
-		var composer ComposerJSON
-		json.Unmarshal(composerData, &composer)
-
-		for dep := range composer.Require {
-			if strings.Contains(dep, "/") {
-				if _, isInternal := pa.Plugins[dep]; isInternal {
-					plugin.Dependencies = append(plugin.Dependencies, dep)
-				} else if pa.ShowExternalDeps {
-					plugin.Dependencies = append(plugin.Dependencies, dep)
-					// Create external plugin node if it doesn't exist
-					if _, exists := pa.Plugins[dep]; !exists {
-						pa.Plugins[dep] = &Plugin{
-							Name:       dep,
-							FolderName: dep,
-							IsExternal: true,
-						}
-					}
-					pa.ExternalDepsCount[dep]++
-				} else {
-					pa.ExternalDepsCount[dep]++
-				}
-			}
+	var previousGraph *analyzer.PluginAnalyzer
+	if *compare != "" {
+		previousGraph, err = analyzer.LoadJSONGraph(*compare)
+		if err != nil {
+			usageFatal(err)
 		}
 	}
 
-	return nil
-}
+	var externalBaseline map[string]int
+	if *externalDelta != "" {
+		externalBaseline, err = analyzer.LoadExternalBaseline(*externalDelta)
+		if err != nil {
+			usageFatal(err)
+		}
+	}
+
+	dotGraphAttrs, err := analyzer.ParseDOTAttributes("graph-attr", graphAttrs)
+	if err != nil {
+		usageFatal(err)
+	}
+	dotNodeAttrs, err := analyzer.ParseDOTAttributes("node-attr", nodeAttrs)
+	if err != nil {
+		usageFatal(err)
+	}
+	dotEdgeAttrs, err := analyzer.ParseDOTAttributes("edge-attr", edgeAttrs)
+	if err != nil {
+		usageFatal(err)
+	}
 
-func (pa *PluginAnalyzer) GenerateMermaid() string {
-	var sb strings.Builder
-	sb.WriteString("graph TD\n")
+	var manifest *analyzer.ArchitectureManifest
+	if *manifestPath != "" {
+		manifest, err = analyzer.LoadManifest(*manifestPath)
+		if err != nil {
+			usageFatal(err)
+		}
+	}
 
-	for _, plugin := range pa.Plugins {
-		if plugin.IsExternal && !pa.ShowExternalDeps {
-			continue
+	var root *analyzer.RootOverrides
+	if *rootComposerPath != "" {
+		root, err = analyzer.LoadRootComposer(*rootComposerPath)
+		if err != nil {
+			usageFatal(err)
 		}
+	}
 
-		for _, dep := range plugin.Dependencies {
-			depPlugin := pa.Plugins[dep]
-			if depPlugin.IsExternal && !pa.ShowExternalDeps {
-				continue
-			}
-			sb.WriteString(fmt.Sprintf("    \"%s\" --> \"%s\"\n", plugin.FolderName, depPlugin.FolderName))
+	var project *analyzer.RootOverrides
+	if *projectPath != "" {
+		project, err = analyzer.LoadRootComposer(*projectPath)
+		if err != nil {
+			usageFatal(err)
 		}
 	}
 
-	return sb.String()
-}
+	var customCSS string
+	if *cssPath != "" {
+		css, err := ioutil.ReadFile(*cssPath)
+		if err != nil {
+			usageFatalf("Failed to read CSS file: %v", err)
+		}
+		customCSS = string(css)
+	}
 
-func (pa *PluginAnalyzer) GenerateGraphviz(outputPath string) error {
-	dotContent := new(strings.Builder)
-	dotContent.WriteString("digraph PluginDependencies {\n")
-	dotContent.WriteString("    rankdir=TB;\n")
-	dotContent.WriteString("    node [shape=box, style=rounded];\n")
-	dotContent.WriteString("    edge [color=\"#666666\"];\n")
+	var theme *analyzer.Theme
+	if *themePath != "" {
+		theme, err = analyzer.LoadTheme(*themePath)
+		if err != nil {
+			usageFatalf("Failed to read -theme: %v", err)
+		}
+	}
 
-	// Add nodes
-	for _, plugin := range pa.Plugins {
-		if plugin.IsExternal && !pa.ShowExternalDeps {
-			continue
+	if *stdout {
+		delete(formats, "graphviz") // the default "both" means mermaid here
+		if len(formats) != 1 || !(formats["mermaid"] || formats["json"] || formats["jsonl"]) {
+			usageFatal("-stdout needs -format mermaid, json or jsonl")
 		}
+	}
 
-		style := "rounded,filled"
-		fillColor := "#f0f0f0"
-		if plugin.IsExternal {
-			fillColor = "#ffe0e0"  // Light red for external deps
+	for _, pattern := range append(append([]string(nil), excludePatterns...), includePatterns...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			usageFatalf("Invalid -exclude or -include pattern %q: %v", pattern, err)
 		}
-		
-		dotContent.WriteString(fmt.Sprintf("    \"%s\" [label=\"%s\", fillcolor=\"%s\", style=\"%s\"];\n",
-			plugin.Name, plugin.FolderName, fillColor, style))
 	}
 
-	// Add edges
-	for _, plugin := range pa.Plugins {
-		if plugin.IsExternal && !pa.ShowExternalDeps {
-			continue
+	if !analyzer.LayoutEngines[*engine] {
+		usageFatalf("Unknown -engine %q (expected dot, neato, fdp, sfdp or circo)", *engine)
+	}
+	if *couplingTable && !analyzer.IsCouplingColumn(*sortBy) {
+		usageFatalf("Invalid -sort-by %q (expected one of %s)", *sortBy, strings.Join(analyzer.CouplingColumns, ", "))
+	}
+
+	var status exitStatus
+	if !*stdout && !*check && !*summaryLine && *serve == "" && (formats["graphviz"] || formats["pdf-report"]) && !analyzer.CheckGraphvizInstalled(*engine) {
+		// The other outputs don't need Graphviz and are still written.
+		log.Printf("Graphviz is not installed (%s not found on PATH). Please install it first; skipping the graphviz and pdf-report formats.", *engine)
+		delete(formats, "graphviz")
+		delete(formats, "pdf-report")
+		status.fail(exitNoGraphviz)
+	}
+
+	if !*stdout && !*check && !*summaryLine {
+		if err := os.MkdirAll(*outputDir, 0755); err != nil {
+			log.Fatalf("Failed to create output directory: %v", err)
+		}
+	}
+
+	pa := analyzer.NewPluginAnalyzer(pluginsDirs, *showExternal)
+	pa.Logger = log.New(os.Stderr, "", log.LstdFlags)
+	if *vendorDir != "" {
+		pa.PluginsDirs = []string{*vendorDir}
+		pa.VendorLayout = true
+	}
+	pa.Recursive = *recursive
+	pa.ZipFile = *zipFile
+	pa.IncludeDev = *includeDev
+	pa.DevOptional = *devOptional
+	pa.ShowSuggest = *showSuggest
+	pa.IncludeBundles = *includeBundles
+	pa.ConstrainInternalOnly = *constrainInternalOnly
+	pa.MergeMutual = *mergeMutual
+	pa.ShowConflict = *showConflict
+	pa.HighlightCycles = !*noCycleHighlight
+	pa.MergeEdges = *mergeEdges
+	pa.ValidateSVG = *validateSVG
+	pa.ImageFormat = *imageFormat
+	pa.Engine = *engine
+	pa.FailFast = *failFast
+	pa.Title = *title
+	pa.IncludePlatform = *includePlatform
+	pa.IgnoredPrefixes = ignoredPrefixes
+	pa.Aliases = aliases
+	pa.OnlyTypes = onlyTypes
+	pa.ExcludePatterns = excludePatterns
+	pa.IgnoreCase = *ignoreCase
+	pa.Workers = *workers
+	if !*noCache {
+		pa.CacheFile = analyzer.DefaultCacheFile
+	}
+	pa.IncludePatterns = includePatterns
+	pa.PluginTypes = pluginTypes
+	pa.ShowLibraries = *showLibraries
+	pa.ColorByDepth = *colorByDepth
+	pa.ColorByConstraint = *colorByConstraint
+	pa.LinkTemplate = *linkTemplate
+	pa.Theme = theme
+	if *lockPath != "" {
+		lock, err := analyzer.LoadComposerLock(*lockPath)
+		if err != nil {
+			usageFatalf("Failed to read -lock: %v", err)
+		}
+		pa.Locked = lock.Versions()
+		pa.Abandoned = lock.AbandonedPackages()
+	}
+	pa.TypoDistance = *typoDistance
+	pa.Denylist = denylist
+	pa.AllowedExternals = allowedExternals
+	pa.Rules = rules
+	pa.Manifest = manifest
+	pa.Root = root
+	pa.MetricsIncludeExternal = *metricsIncludeExternal
+	pa.GraphAttrs = dotGraphAttrs
+	pa.NodeAttrs = dotNodeAttrs
+	pa.EdgeAttrs = dotEdgeAttrs
+	pa.ClusterBy = *clusterBy
+	pa.InternalPrefixes = internalPrefixes
+	pa.ForcedExternalPrefixes = forcedExternal
+	done := timer.track("scan")
+	if err := pa.ScanPlugins(); err != nil {
+		var dirErr *analyzer.PluginsDirError
+		if errors.As(err, &dirErr) || errors.Is(err, analyzer.ErrNoPlugins) ||
+			errors.Is(err, fs.ErrNotExist) || errors.Is(err, zip.ErrFormat) {
+			usageFatal(err)
 		}
+		log.Printf("Failed to scan plugins: %v", err)
+		os.Exit(exitScanErrors)
+	}
+	done()
 
-		for _, dep := range plugin.Dependencies {
-			depPlugin := pa.Plugins[dep]
-			if depPlugin.IsExternal && !pa.ShowExternalDeps {
-				continue
+	if len(changed) > 0 {
+		pa.Changed = make(map[string]bool)
+		for _, name := range changed {
+			if plugin := pa.FindPlugin(name); plugin != nil {
+				pa.Changed[plugin.Name] = true
+			} else {
+				warnf("changed plugin %q not found", name)
 			}
-			dotContent.WriteString(fmt.Sprintf("    \"%s\" -> \"%s\";\n", plugin.Name, dep))
 		}
 	}
 
-	dotContent.WriteString("}\n")
+	if *vendorScope != "" {
+		scoped, err := pa.VendorScope(*vendorScope)
+		if err != nil {
+			usageFatal(err)
+		}
+		pa = scoped
+	}
 
-	// Write to temporary file
-	tmpFile, err := os.CreateTemp("", "deps*.dot")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+	if project != nil {
+		scoped, err := pa.ProjectScope(project)
+		if err != nil {
+			usageFatalf("%s: %v", *projectPath, err)
+		}
+		pa = scoped
 	}
-	defer os.Remove(tmpFile.Name())
 
-	if _, err := tmpFile.WriteString(dotContent.String()); err != nil {
-		return fmt.Errorf("failed to write DOT content: %w", err)
+	if *rootPlugin != "" {
+		scoped, err := pa.RootScope(*rootPlugin)
+		if err != nil {
+			usageFatal(err)
+		}
+		pa = scoped
 	}
-	tmpFile.Close()
 
-	// Run dot command to generate SVG
-	cmd := exec.Command("dot", "-Tsvg", "-o", outputPath, tmpFile.Name())
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run dot command: %w", err)
+	if *focus != "" {
+		scoped, err := pa.Subgraph(*focus, *focusDepth)
+		if err != nil {
+			usageFatal(err)
+		}
+		pa = scoped
 	}
 
-	return nil
-}
+	if *interactive {
+		if !isTerminal(os.Stdin) {
+			warnf("-interactive needs a terminal on stdin, showing all plugins")
+		} else if selected := selectPlugins(pa, os.Stdin, os.Stderr); len(selected) > 0 {
+			scoped, err := pa.SubgraphOf(selected, *focusDepth)
+			if err != nil {
+				usageFatal(err)
+			}
+			pa = scoped
+		}
+	}
 
-func checkGraphvizInstalled() bool {
-	_, err := exec.LookPath("dot")
-	return err == nil
-}
+	var exposed []analyzer.ExposedPlugin
+	if *externalProximity > 0 {
+		exposed = pa.ExternalProximity()
+		scoped, err := pa.ProximityScope(*externalProximity)
+		if err != nil {
+			usageFatal(err)
+		}
+		pa = scoped
+	}
 
-func main() {
-	pluginsDir := flag.String("dir", "", "Directory containing plugin folders")
-	outputFormat := flag.String("format", "both", "Output format: mermaid, graphviz, or both")
-	outputDir := flag.String("output", "output", "Output directory for generated files")
-	showExternal := flag.Bool("show-external", false, "Include external dependencies in the graph")
-	flag.Parse()
+	if *pathFlag != "" {
+		from, to, err := analyzer.ParsePathFlag(*pathFlag)
+		if err != nil {
+			usageFatal(err)
+		}
+		if path, ok := pa.FindPath(from, to); ok {
+			pa.HighlightPath = path
+		} else {
+			log.Printf("No dependency path from %s to %s", from, to)
+			status.fail(exitFailure)
+		}
+	}
 
-	if *pluginsDir == "" {
-		log.Fatal("Please specify plugins directory with -dir flag")
+	if *watch {
+		runWatch(pa)
+		stopProfile()
+		os.Exit(exitOK)
+	}
+	if *watchServe != "" {
+		log.Fatal(runWatchServe(pa, *watchServe))
+	}
+	if *serve != "" {
+		log.Fatal(runServe(pa, *serve, customCSS))
 	}
 
-	if !checkGraphvizInstalled() {
-		log.Fatal("Graphviz is not installed. Please install it first.")
+	if *check {
+		code := runCheck(pa)
+		timer.print()
+		stopProfile()
+		os.Exit(code)
 	}
 
-	if err := os.MkdirAll(*outputDir, 0755); err != nil {
-		log.Fatalf("Failed to create output directory: %v", err)
+	if *summaryLine {
+		fmt.Println(pa.SummaryLine())
+		code := gateCycles(pa, *maxCycles, *assertDAG)
+		timer.print()
+		stopProfile()
+		os.Exit(code)
 	}
 
-	analyzer := NewPluginAnalyzer(*pluginsDir, *showExternal)
-	if err := analyzer.ScanPlugins(); err != nil {
-		log.Fatalf("Failed to scan plugins: %v", err)
+	var outdated []analyzer.OutdatedPackage
+	if *checkUpdates {
+		done := timer.track("check updates")
+		outdated = pa.CheckUpdates(analyzer.NewPackagistClient(*updateTimeout))
+		done()
 	}
 
-	if *outputFormat == "mermaid" || *outputFormat == "both" {
-		mermaid := analyzer.GenerateMermaid()
-		mermaidPath := filepath.Join(*outputDir, "dependencies.mmd")
+	// The rendered graphs may be cut at a depth, collapse vendors, merge
+	// rarely used external packages and hide hubs and pass-through chains;
+	// analyses and data exports always see the full graph.
+	graph := pa
+	if *maxDepth >= 0 {
+		graph = graph.LimitDepth(*maxDepth, *depthPlaceholders)
+	}
+	var collapsed []analyzer.CollapsedVendor
+	if len(collapseVendors) > 0 {
+		graph, collapsed = graph.CollapseVendors(collapseVendors)
+		if len(collapsed) < len(collapseVendors) {
+			found := make(map[string]bool)
+			for _, c := range collapsed {
+				found[c.Vendor] = true
+			}
+			for _, vendor := range collapseVendors {
+				if !found[strings.ToLower(strings.TrimSpace(vendor))] {
+					warnf("-collapse-vendor %q: no plugins of this vendor found", vendor)
+				}
+			}
+		}
+	}
+	var otherExternals []string
+	if *maxExternal >= 0 {
+		graph, otherExternals = graph.BucketExternals(*maxExternal)
+	}
+	if *hideAboveFanout > 0 {
+		graph = graph.HideHubs(*hideAboveFanout, *hubStubs)
+	}
+	if *collapseChains {
+		graph = graph.CollapseChains()
+	}
+
+	if *stdout {
+		done := timer.track("stdout")
+		if formats["json"] {
+			data, err := pa.GenerateJSON()
+			if err != nil {
+				log.Fatalf("Failed to generate JSON: %v", err)
+			}
+			os.Stdout.Write(data)
+		} else if formats["jsonl"] {
+			if err := pa.StreamJSONL(os.Stdout); err != nil {
+				log.Fatalf("Failed to write JSON Lines: %v", err)
+			}
+		} else {
+			fmt.Print(graph.GenerateMermaid())
+		}
+		done()
+		status.fail(gateCycles(pa, *maxCycles, *assertDAG))
+		timer.print()
+		stopProfile()
+		os.Exit(int(status))
+	}
+
+	if formats["mermaid"] {
+		done := timer.track("mermaid")
+		mermaid := graph.GenerateMermaid()
+		done()
+		mermaidPath := filepath.Join(*outputDir, *baseName+".mmd")
 		if err := ioutil.WriteFile(mermaidPath, []byte(mermaid), 0644); err != nil {
 			log.Printf("Failed to write Mermaid file: %v", err)
+			status.fail(exitFailure)
+		} else {
+			progressf("Mermaid graph saved to %s\n", mermaidPath)
+		}
+	}
+
+	if formats["graphviz"] {
+		imagePath := filepath.Join(*outputDir, *baseName+"."+*imageFormat)
+		done := timer.track("graphviz")
+		err := graph.GenerateGraphviz(imagePath)
+		done()
+		if err != nil {
+			logGenerateFailure(strings.ToUpper(*imageFormat), err)
+			status.fail(exitFailure)
+		} else {
+			progressf("%s graph saved to %s\n", strings.ToUpper(*imageFormat), imagePath)
+		}
+	}
+
+	if formats["pdf-report"] {
+		pdfPath := filepath.Join(*outputDir, "report.pdf")
+		done := timer.track("pdf-report")
+		err := graph.GeneratePDFReport(pdfPath)
+		done()
+		if err != nil {
+			logGenerateFailure("PDF report", err)
+			status.fail(exitFailure)
+		} else {
+			progressf("PDF report saved to %s\n", pdfPath)
+		}
+	}
+
+	if formats["html"] {
+		done := timer.track("html")
+		report, err := graph.GenerateHTML(customCSS)
+		done()
+		if err != nil {
+			log.Printf("Failed to generate HTML report: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, "report.html"), []byte(report), "HTML report")
+		}
+	}
+
+	if formats["html-interactive"] {
+		done := timer.track("html-interactive")
+		page, err := graph.GenerateInteractiveHTML()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate interactive HTML: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, "interactive.html"), []byte(page), "Interactive HTML graph")
+		}
+	}
+
+	if formats["cypher"] {
+		done := timer.track("cypher")
+		cypher := pa.GenerateCypher()
+		done()
+		writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".cypher"), []byte(cypher), "Cypher statements")
+	}
+
+	if formats["structurizr"] {
+		done := timer.track("structurizr")
+		dsl := pa.GenerateStructurizr()
+		done()
+		writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".dsl"), []byte(dsl), "Structurizr workspace")
+	}
+
+	if formats["markdown"] {
+		done := timer.track("markdown")
+		report := graph.GenerateMarkdown()
+		done()
+		writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".md"), []byte(report), "Markdown report")
+	}
+
+	if formats["plantuml"] {
+		done := timer.track("plantuml")
+		diagram := graph.GeneratePlantUML()
+		done()
+		writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".puml"), []byte(diagram), "PlantUML diagram")
+	}
+
+	if formats["csv"] {
+		done := timer.track("csv")
+		edges, err := pa.GenerateCSV()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate CSV: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".csv"), []byte(edges), "CSV edge list")
+		}
+	}
+
+	if formats["jsonl"] {
+		done := timer.track("jsonl")
+		err := writeJSONL(pa, filepath.Join(*outputDir, *baseName+".jsonl"))
+		done()
+		if err != nil {
+			log.Printf("Failed to write JSON Lines graph: %v", err)
+			status.fail(exitFailure)
+		}
+	}
+
+	if formats["json"] {
+		done := timer.track("json")
+		data, err := pa.GenerateJSON()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate JSON: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".json"), data, "JSON graph")
+		}
+	}
+
+	if formats["dgml"] {
+		done := timer.track("dgml")
+		dgml, err := pa.GenerateDGML()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate DGML: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".dgml"), dgml, "DGML graph")
+		}
+	}
+
+	if formats["graphml"] {
+		done := timer.track("graphml")
+		graphml, err := pa.GenerateGraphML()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate GraphML: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, *baseName+".graphml"), []byte(graphml), "GraphML graph")
+		}
+	}
+
+	if formats["ascii"] {
+		done := timer.track("ascii")
+		diagram := graph.GenerateASCII(*asciiMaxNodes)
+		done()
+		fmt.Printf("\nDependency Graph:\n\n%s", diagram)
+	}
+
+	if *sarifPath != "" {
+		done := timer.track("sarif")
+		sarif, err := pa.GenerateSARIF()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate SARIF: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, *sarifPath, sarif, "SARIF report")
+		}
+	}
+
+	if *splitJSON {
+		dir := filepath.Join(*outputDir, "plugins")
+		done := timer.track("split-json")
+		n, err := pa.WriteSplitJSON(dir)
+		done()
+		if err != nil {
+			log.Printf("Failed to write per-plugin JSON: %v", err)
+			status.fail(exitFailure)
+		} else {
+			progressf("%d plugin JSON files saved to %s\n", n, dir)
+		}
+	}
+
+	if *protobufPath != "" {
+		done := timer.track("protobuf")
+		graph := pa.GenerateProtobuf()
+		done()
+		writeOutputFile(&status, *protobufPath, graph, "Protobuf graph")
+	}
+
+	if *iciclePath != "" {
+		done := timer.track("icicle")
+		icicle, err := pa.GenerateIcicle(icicleRoots)
+		done()
+		if err != nil {
+			log.Printf("Failed to generate icicle diagram: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, *iciclePath, []byte(icicle), "Icicle diagram")
+		}
+	}
+
+	if *otelPath != "" {
+		done := timer.track("otel-attributes")
+		attributes, err := pa.GenerateOTelAttributes()
+		done()
+		if err != nil {
+			log.Printf("Failed to generate OpenTelemetry attributes: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, *otelPath, attributes, "OpenTelemetry attributes")
+		}
+	}
+
+	if len(adrFocus) > 0 {
+		snapshot, err := pa.GenerateADRSnapshot(adrFocus)
+		if err != nil {
+			log.Printf("Failed to generate ADR snapshot: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, filepath.Join(*outputDir, "adr-snapshot.md"), []byte(snapshot), "ADR snapshot")
+		}
+	}
+
+	if *dependentsOf != "" {
+		dependents, err := pa.Dependents(*dependentsOf, *transitive)
+		if err != nil {
+			log.Printf("Failed to list dependents: %v", err)
+			status.fail(exitUsage)
+		} else {
+			fmt.Printf("\nDependents of %s:\n", *dependentsOf)
+			if len(dependents) == 0 {
+				fmt.Println("  none")
+			}
+			for _, folder := range dependents {
+				fmt.Printf("  %s\n", folder)
+			}
+		}
+	}
+
+	if *closureOf != "" {
+		closure, err := pa.Closure(*closureOf)
+		if err != nil {
+			log.Printf("Failed to list dependency closure: %v", err)
+			status.fail(exitUsage)
+		} else {
+			fmt.Printf("\nDependency Closure of %s:\n", *closureOf)
+			if len(closure) == 0 {
+				fmt.Println("  none")
+			}
+			for _, folder := range closure {
+				fmt.Printf("  %s\n", folder)
+			}
+		}
+	}
+
+	if *explain != "" {
+		explanation, err := pa.Explain(*explain)
+		if err != nil {
+			log.Printf("Failed to explain plugin: %v", err)
+			status.fail(exitUsage)
+		} else {
+			fmt.Printf("\n%s", explanation)
+		}
+	}
+
+	if len(pa.HighlightPath) > 0 {
+		fmt.Println("\nDependency Path:")
+		fmt.Printf("  %s\n", pa.FormatPath(pa.HighlightPath))
+	}
+
+	if *printOrder {
+		fmt.Println("\nInstall Order:")
+		order, err := pa.InstallOrder()
+		if err != nil {
+			log.Printf("Failed to compute install order: %v", err)
+			order = nil
+		}
+		for _, folder := range order {
+			fmt.Printf("  %s\n", folder)
+		}
+	}
+
+	if *planFrom != "" {
+		fmt.Println("\nDeployment Plan:")
+		var plan []analyzer.PlanOperation
+		previous, err := pa.ScanState(*planFrom)
+		if err == nil {
+			plan, err = pa.DeploymentPlan(previous)
+		}
+		if err != nil {
+			log.Printf("Failed to compute deployment plan: %v", err)
+		} else if len(plan) == 0 {
+			fmt.Println("  none")
+		}
+		for i, op := range plan {
+			fmt.Printf("  %d. %s\n", i+1, op)
+		}
+	}
+
+	if *installScript != "" {
+		install, uninstall, err := pa.GenerateInstallScripts()
+		if err != nil {
+			log.Printf("Failed to generate install scripts: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeScript(&status, *installScript, install, "Install script")
+			writeScript(&status, analyzer.UninstallScriptPath(*installScript), uninstall, "Uninstall script")
+		}
+	}
+
+	if *bomPath != "" {
+		locked := pa.Locked
+		if *bomLock != "" {
+			lock, err := analyzer.LoadComposerLock(*bomLock)
+			if err != nil {
+				log.Printf("Failed to read -bom-lock: %v", err)
+			} else {
+				locked = lock.Versions()
+			}
+		}
+		entries := pa.BillOfMaterials(locked)
+		var bom []byte
+		var err error
+		if strings.EqualFold(filepath.Ext(*bomPath), ".csv") {
+			bom, err = analyzer.GenerateBOMCSV(entries)
+		} else {
+			bom, err = analyzer.GenerateBOMJSON(entries)
+		}
+		if err != nil {
+			log.Printf("Failed to generate bill of materials: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, *bomPath, bom, "Bill of materials")
 		}
-		fmt.Printf("Mermaid graph saved to %s\n", mermaidPath)
 	}
 
-	if *outputFormat == "graphviz" || *outputFormat == "both" {
-		svgPath := filepath.Join(*outputDir, "dependencies.svg")
-		if err := analyzer.GenerateGraphviz(svgPath); err != nil {
-			log.Printf("Failed to generate SVG: %v", err)
+	if *externalCounts != "" {
+		counts, err := pa.GenerateExternalCounts()
+		if err != nil {
+			log.Printf("Failed to encode external usage counts: %v", err)
+			status.fail(exitFailure)
 		} else {
-			fmt.Printf("SVG graph saved to %s\n", svgPath)
+			writeOutputFile(&status, *externalCounts, counts, "External usage counts")
+		}
+	}
+
+	if *upload != "" {
+		token := *uploadToken
+		if token == "" {
+			token = os.Getenv(uploadTokenEnv)
+		}
+		if url, err := uploadDiagram(*upload, token, graph.GenerateMermaid(), *uploadInsecure); err != nil {
+			log.Printf("Failed to upload Mermaid graph: %v", err)
+			status.fail(exitFailure)
+		} else {
+			fmt.Printf("Mermaid graph shared at %s\n", url)
 		}
 	}
 
 	// Print summary
-	fmt.Println("\nInternal Dependencies Summary:")
-	for _, plugin := range analyzer.Plugins {
-		if plugin.IsExternal {
-			continue
+	fmt.Printf("\n%s", pa.Summary())
+
+	if len(collapsed) > 0 {
+		fmt.Println("\nCollapsed Vendors:")
+		for _, c := range collapsed {
+			fmt.Printf("  %s: %s\n", c.Node, strings.Join(c.Plugins, ", "))
 		}
-		if len(plugin.Dependencies) > 0 {
-			fmt.Printf("\n%s:\n", plugin.FolderName)
-			for _, dep := range plugin.Dependencies {
-				depPlugin := analyzer.Plugins[dep]
-				if depPlugin.IsExternal {
-					fmt.Printf("  ├─ %s (external)\n", dep)
-				} else {
-					fmt.Printf("  ├─ %s\n", depPlugin.FolderName)
-				}
+	}
+
+	if len(otherExternals) > 0 {
+		fmt.Printf("\nOther Externals (drawn as one node):\n  %s\n", strings.Join(otherExternals, ", "))
+	}
+
+	if *tree {
+		fmt.Println("\nDependency Tree:")
+		if text := pa.GenerateTree(); text == "" {
+			fmt.Println("  none")
+		} else {
+			fmt.Print(text)
+		}
+	}
+
+	if *showAll {
+		var independent []string
+		for _, plugin := range pa.Plugins {
+			if !plugin.IsExternal && len(plugin.Dependencies) == 0 {
+				independent = append(independent, plugin.FolderName)
 			}
 		}
+		sort.Strings(independent)
+
+		fmt.Println("\nPlugins Without Dependencies:")
+		if len(independent) == 0 {
+			fmt.Println("  none")
+		}
+		for _, folder := range independent {
+			fmt.Printf("  %s\n", folder)
+		}
 	}
 
-	// Print external dependencies summary
-	if len(analyzer.ExternalDepsCount) > 0 {
-		fmt.Println("\nExternal Dependencies Summary:")
-		for dep, count := range analyzer.ExternalDepsCount {
-			fmt.Printf("  %s: used by %d plugin(s)\n", dep, count)
+	if orphans := pa.Orphans(); len(orphans) > 0 {
+		fmt.Println("\nIsolated Plugins:")
+		for _, folder := range orphans {
+			fmt.Printf("  %s\n", folder)
+		}
+	}
+
+	if summary := pa.ExternalSummary(); summary != "" {
+		fmt.Printf("\n%s", summary)
+	}
+
+	if *externalDelta != "" {
+		fmt.Println("\nExternal Dependency Delta:")
+		deltas := pa.ExternalDeltas(externalBaseline)
+		if len(deltas) == 0 {
+			fmt.Println("  none")
+		}
+		for _, d := range deltas {
+			switch d.Change {
+			case analyzer.DeltaAdded:
+				fmt.Printf("  + %s: new, used by %d plugin(s)\n", d.Package, d.Current)
+			case analyzer.DeltaRemoved:
+				fmt.Printf("  - %s: no longer used (was %d)\n", d.Package, d.Baseline)
+			default:
+				fmt.Printf("  ~ %s: %s from %d to %d plugin(s)\n", d.Package, d.Change, d.Baseline, d.Current)
+			}
+		}
+	}
+
+	if previousGraph != nil {
+		fmt.Printf("\nGraph Changes Since %s:\n", *compare)
+		diff := analyzer.Diff(previousGraph, pa)
+		if diff.Empty() {
+			fmt.Println("  none")
+		}
+		for _, folder := range diff.AddedPlugins {
+			fmt.Printf("  + %s\n", folder)
+		}
+		for _, folder := range diff.RemovedPlugins {
+			fmt.Printf("  - %s\n", folder)
+		}
+		for _, e := range diff.AddedEdges {
+			fmt.Printf("  + %s → %s (%s)\n", e.From, e.To, e.Kind)
+		}
+		for _, e := range diff.RemovedEdges {
+			fmt.Printf("  - %s → %s (%s)\n", e.From, e.To, e.Kind)
+		}
+	}
+
+	if deprecated := pa.DeprecatedDependencies(); len(deprecated) > 0 {
+		fmt.Println("\nDependencies on Deprecated Plugins:")
+		for _, d := range deprecated {
+			fmt.Printf("  %s depends on deprecated %s\n", d.Plugin, d.Target)
+		}
+	}
+
+	if *minShopware != "" {
+		fmt.Printf("\nPlugins Blocking Shopware %s:\n", *minShopware)
+		blockers, err := pa.ShopwareBlockers(*minShopware)
+		if err != nil {
+			log.Printf("Failed to check -min-shopware: %v", err)
+		} else if len(blockers) == 0 {
+			fmt.Println("  none")
+		}
+		for _, b := range blockers {
+			fmt.Printf("  %s requires shopware/core %s\n", b.Plugin, b.Constraint)
+		}
+	}
+
+	if conflicts := pa.PluginConflicts(); len(conflicts) > 0 {
+		fmt.Println("\nConflicts Detected:")
+		for _, c := range conflicts {
+			fmt.Printf("  %s conflicts with %s %s (%s found)\n", c.Plugin, c.Target, c.Constraint, analyzer.VersionLabel(pa.PluginByFolder(c.Target)))
+		}
+	}
+
+	if mismatches := pa.VersionMismatches(); len(mismatches) > 0 {
+		fmt.Println("\nVersion Mismatches:")
+		for _, m := range mismatches {
+			fmt.Printf("  %s requires %s %s, but %s is at %s\n", m.Plugin, m.Target, m.Constraint, m.Target, m.Version)
+		}
+	}
+
+	if len(pa.InternalPrefixes) > 0 {
+		fmt.Println("\nMissing Internal Plugins:")
+		missing := pa.MissingInternalDependencies()
+		if len(missing) == 0 {
+			fmt.Println("  none")
+		}
+		for _, m := range missing {
+			fmt.Printf("  %s (required by %s)\n", m.Name, strings.Join(m.RequiredBy, ", "))
+		}
+	}
+
+	if ambiguous := pa.AmbiguousProviders(); len(ambiguous) > 0 {
+		fmt.Println("\nAmbiguous Providers:")
+		for _, a := range ambiguous {
+			fmt.Printf("  Warning: %s is provided by %s; requirements on it are drawn to %s\n",
+				a.Package, strings.Join(a.Providers, ", "), a.Providers[0])
+		}
+	}
+
+	if redundant := pa.RedundantDevRequirements(); len(redundant) > 0 {
+		fmt.Println("\nRedundant Dev Requirements:")
+		for _, r := range redundant {
+			fmt.Printf("  Warning: %s lists %s in both require and require-dev\n", r.Plugin, r.Package)
+		}
+	}
+
+	if mutual := pa.MutualDependencies(); len(mutual) > 0 {
+		fmt.Println("\nMutual Dependencies:")
+		for _, m := range mutual {
+			fmt.Printf("  %s ↔ %s\n", m.A, m.B)
+		}
+	}
+
+	if redundant := pa.RedundantDependencies(); len(redundant) > 0 {
+		fmt.Println("\nRedundant Direct Dependencies:")
+		for _, r := range redundant {
+			fmt.Printf("  %s requires %s, which it already gets through %s\n", r.Plugin, r.Dependency, r.Via)
+		}
+	}
+
+	if typos := pa.PossibleTypos(pa.TypoDistance); len(typos) > 0 {
+		fmt.Println("\nPossible Typos:")
+		for _, t := range typos {
+			fmt.Printf("  %s requires %s; did you mean %s?\n", t.Plugin, t.Required, t.Suggestion)
+		}
+	}
+
+	if *checkUpdates {
+		fmt.Println("\nOutdated External Dependencies:")
+		if len(outdated) == 0 {
+			fmt.Println("  none")
+		}
+		for _, o := range outdated {
+			fmt.Printf("  %s (latest %s):\n", o.Package, o.Latest)
+			for _, req := range o.Requirements {
+				fmt.Printf("    ├─ %s requires %s\n", req.Plugin, req.Constraint)
+			}
+		}
+	}
+
+	if abandoned := pa.AbandonedDependencies(); len(abandoned) > 0 {
+		fmt.Println("\nAbandoned Dependencies:")
+		for _, a := range abandoned {
+			replacement := "no replacement suggested"
+			if a.Replacement != "" {
+				replacement = "use " + a.Replacement + " instead"
+			}
+			fmt.Printf("  Warning: %s is abandoned, %s (required by %s)\n", a.Package, replacement, strings.Join(a.Users, ", "))
+		}
+	}
+
+	if *compareLock {
+		fmt.Println("\nLock File Drift:")
+		drifts, err := pa.CompareLocks()
+		if err != nil {
+			log.Printf("Failed to compare lock files: %v", err)
+		} else if len(drifts) == 0 {
+			fmt.Println("  none")
+		}
+		for _, d := range drifts {
+			fmt.Printf("  %s: %s locked at %s, but composer.json requires %s\n", d.Plugin, d.Package, d.Locked, d.Constraint)
+		}
+	}
+
+	if *externalProximity > 0 {
+		fmt.Printf("\nExternal Exposure (within %d hop(s)):\n", *externalProximity)
+		for _, p := range exposed {
+			if p.Hops <= *externalProximity {
+				fmt.Printf("  %s: %d hop(s)\n", p.FolderName, p.Hops)
+			}
+		}
+	}
+
+	// Policy violations are reported after all outputs have been written
+	// and make the run fail.
+
+	if len(pa.ScanErrors) > 0 {
+		fmt.Println("\nScan Warnings:")
+		for _, scanErr := range pa.ScanErrors {
+			fmt.Printf("  %s: skipped, %s\n", filepath.Join(scanErr.Dir, scanErr.Folder), scanErr.Reason)
+		}
+		if *strict {
+			status.fail(exitScanErrors)
+		}
+	}
+
+	if duplicates := pa.DuplicatePluginNames(); len(duplicates) > 0 {
+		fmt.Println("\nDuplicate Plugin Names:")
+		for _, d := range duplicates {
+			fmt.Printf("  %s: %s kept, %s ignored\n", d.Name, d.Kept, strings.Join(d.Ignored, ", "))
+		}
+		if *strict {
+			status.fail(exitScanErrors)
+		}
+	}
+
+	if duplicates := pa.DuplicatePluginClasses(); len(duplicates) > 0 {
+		fmt.Println("\nDuplicate Plugin Classes:")
+		for _, d := range duplicates {
+			fmt.Printf("  %s: %s\n", d.Class, strings.Join(d.Plugins, ", "))
+		}
+		if *strict {
+			status.fail(exitScanErrors)
+		}
+	}
+
+	done = timer.track("cycle detection")
+	cycles := pa.DetectCycles()
+	done()
+	fmt.Println("\nCircular Dependencies:")
+	if len(cycles) == 0 {
+		fmt.Println("  none")
+	}
+	for _, cycle := range cycles {
+		fmt.Printf("  %s\n", pa.FormatCycle(cycle))
+	}
+	if len(cycles) > 0 && (*assertDAG || len(cycles) > *maxCycles) {
+		status.fail(exitCycles)
+	}
+
+	if *denylistPath != "" {
+		fmt.Println("\nDenied Packages:")
+		denied := pa.DeniedRequirements()
+		if len(denied) == 0 {
+			fmt.Println("  none")
+		}
+		for _, d := range denied {
+			section := "require"
+			if d.Dev {
+				section = "require-dev"
+			}
+			fmt.Printf("  %s requires %s (%s, denied by %q)\n", d.Plugin, d.Package, section, d.Pattern)
+		}
+		if len(denied) > 0 {
+			status.fail(exitFailure)
+		}
+	}
+
+	if *allowedExternalsPath != "" {
+		fmt.Println("\nDisallowed External Dependencies:")
+		disallowed := pa.DisallowedExternals()
+		if len(disallowed) == 0 {
+			fmt.Println("  none")
+		}
+		for _, d := range disallowed {
+			fmt.Printf("  %s (required by %s)\n", d.Package, strings.Join(d.Users, ", "))
+		}
+		if len(disallowed) > 0 && *strict {
+			status.fail(exitFailure)
+		}
+	}
+
+	if *rulesPath != "" {
+		fmt.Println("\nArchitecture Rule Violations:")
+		violations := pa.RuleViolations()
+		if len(violations) == 0 {
+			fmt.Println("  none")
+		}
+		for _, v := range violations {
+			fmt.Printf("  %s → %s (%s) violates %s (line %d)\n", v.From, v.To, v.Kind, v.Rule, v.Rule.Line)
+		}
+		if len(violations) > 0 {
+			status.fail(exitFailure)
+		}
+	}
+
+	if *assertEdges != "" {
+		fmt.Printf("\nExpected Edges (%s):\n", *assertEdges)
+		missing, unexpected := pa.CompareEdges(expectedEdges)
+		if len(missing)+len(unexpected) == 0 {
+			fmt.Println("  match")
+		}
+		for _, e := range missing {
+			fmt.Printf("  - %s → %s (missing)\n", e.From, e.To)
+		}
+		for _, e := range unexpected {
+			fmt.Printf("  + %s → %s (unexpected)\n", e.From, e.To)
+		}
+		if len(missing)+len(unexpected) > 0 {
+			status.fail(exitFailure)
+		}
+	}
+
+	if len(forbidDependents) > 0 {
+		forbidden, unknown := pa.ForbiddenDependents(forbidDependents)
+		for _, name := range unknown {
+			warnf("-forbid-dependents plugin %q not found", name)
+		}
+		fmt.Println("\nForbidden Dependents:")
+		if len(forbidden) == 0 {
+			fmt.Println("  none")
+		}
+		for _, f := range forbidden {
+			fmt.Printf("  %s → %s\n", f.Plugin, f.Target)
+		}
+		if len(forbidden) > 0 {
+			status.fail(exitFailure)
+		}
+	}
+
+	if *checkUnused {
+		fmt.Println("\nPossibly Unused Dependencies:")
+		unused, err := pa.UnusedDependencies()
+		if err != nil {
+			log.Printf("Failed to search plugin sources: %v", err)
+		} else if len(unused) == 0 {
+			fmt.Println("  none")
+		}
+		for _, u := range unused {
+			fmt.Printf("  %s requires %s but never references its namespace\n", u.Plugin, u.Target)
+		}
+	}
+
+	if *roots {
+		fmt.Println("\nRoot Plugins (no internal dependents):")
+		list := pa.Roots()
+		if len(list) == 0 {
+			fmt.Println("  none")
+		}
+		for _, folder := range list {
+			fmt.Printf("  %s\n", folder)
+		}
+	}
+
+	if *leaves {
+		fmt.Println("\nLeaf Plugins (no internal dependencies):")
+		list := pa.Leaves()
+		if len(list) == 0 {
+			fmt.Println("  none")
+		}
+		for _, folder := range list {
+			fmt.Printf("  %s\n", folder)
+		}
+	}
+
+	if manifest != nil {
+		fmt.Println("\nManifest Verification:")
+		deviations := pa.VerifyManifest(manifest)
+		if len(deviations) == 0 {
+			fmt.Println("  graph matches the manifest")
+		}
+		for _, d := range deviations {
+			fmt.Printf("  %s: %s → %s\n", d.Kind, d.From, d.To)
+		}
+		if len(deviations) > 0 {
+			status.fail(exitFailure)
+		}
+	}
+
+	if *couplingTable {
+		rows, _ := pa.CouplingTable(*sortBy) // -sort-by was validated above
+		fmt.Println("\nCoupling:")
+		if len(rows) == 0 {
+			fmt.Println("  none")
+		} else {
+			fmt.Print(analyzer.FormatCouplingTable(rows))
+		}
+	}
+
+	if *couplingReport {
+		fmt.Println("\nCoupling Report:")
+		report := pa.CouplingReport()
+		if len(report) == 0 {
+			fmt.Println("  none")
+		}
+		for _, m := range report {
+			fmt.Printf("  %s: fan-in %d, fan-out %d\n", m.FolderName, m.FanIn, m.FanOut)
 		}
 	}
-}
 
+	if *hotspots {
+		fmt.Printf("\nCoupling Hotspots (fan-in > %d and fan-out > %d):\n", *hotspotFanIn, *hotspotFanOut)
+		list := pa.CouplingHotspots(*hotspotFanIn, *hotspotFanOut)
+		if len(list) == 0 {
+			fmt.Println("  none")
+		}
+		for _, m := range list {
+			fmt.Printf("  %s: fan-in %d, fan-out %d\n", m.FolderName, m.FanIn, m.FanOut)
+		}
+	}
+
+	stats := pa.Stats()
+	fmt.Printf("\n%s", analyzer.FormatStats(stats))
+	budget := analyzer.StatsBudget{MaxEdges: *maxEdges, MaxCycles: *maxCycles, MaxDepth: *maxDepthAllowed}
+	if exceeded := budget.Exceeded(stats); len(exceeded) > 0 {
+		fmt.Println("\nBudget Exceeded:")
+		for _, line := range exceeded {
+			fmt.Printf("  %s\n", line)
+		}
+		// Too many cycles already failed the run with exitCycles above.
+		status.fail(exitFailure)
+	}
+	if *statsJSON != "" {
+		if data, err := analyzer.GenerateStatsJSON(stats); err != nil {
+			log.Printf("Failed to generate statistics: %v", err)
+			status.fail(exitFailure)
+		} else {
+			writeOutputFile(&status, *statsJSON, data, "Graph statistics")
+		}
+	}
+
+	if *githubAnnotations {
+		fmt.Print("\n", pa.GitHubAnnotations())
+	}
+
+	timer.print()
+	stopProfile()
+
+	os.Exit(int(status))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe or a file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// selectPlugins lists the internal plugins numbered on out and reads the
+// ones to focus on from in: numbers or folder or composer names, separated
+// by commas or spaces. Invalid input is asked for again. It returns the
+// composer names of the selected plugins, or nil if the answer was empty or
+// in ended, which means no selection.
+func selectPlugins(pa *analyzer.PluginAnalyzer, in io.Reader, out io.Writer) []string {
+	names := pa.InternalPluginNames()
+	fmt.Fprintln(out, "Plugins:")
+	for i, name := range names {
+		fmt.Fprintf(out, "  %3d  %s\n", i+1, pa.Plugins[name].FolderName)
+	}
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "Focus on (numbers or names, empty for all): ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return nil
+		}
+		fields := strings.FieldsFunc(scanner.Text(), func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(fields) == 0 {
+			return nil
+		}
+
+		var selected []string
+		var invalid []string
+		for _, field := range fields {
+			if n, err := strconv.Atoi(field); err == nil {
+				if n < 1 || n > len(names) {
+					invalid = append(invalid, field)
+					continue
+				}
+				selected = append(selected, names[n-1])
+				continue
+			}
+			plugin := pa.FindPlugin(field)
+			if plugin == nil {
+				invalid = append(invalid, field)
+				continue
+			}
+			selected = append(selected, plugin.Name)
+		}
+		if len(invalid) == 0 {
+			return selected
+		}
+		fmt.Fprintf(out, "Unknown plugin %s, try again.\n", strings.Join(invalid, ", "))
+	}
+}
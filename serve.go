@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// graphServer serves the current graph over HTTP and notifies connected
+// browsers through Server-Sent Events when it changes.
+type graphServer struct {
+	mu       sync.RWMutex
+	analyzer *analyzer.PluginAnalyzer
+	svg      []byte
+	svgErr   error
+	version  int
+	clients  map[chan int]bool
+}
+
+func newGraphServer(pa *analyzer.PluginAnalyzer) *graphServer {
+	s := &graphServer{clients: make(map[chan int]bool)}
+	s.update(pa)
+	return s
+}
+
+// update renders the graph of pa and makes it the one being served.
+func (s *graphServer) update(pa *analyzer.PluginAnalyzer) {
+	svg, err := pa.RenderSVG()
+
+	s.mu.Lock()
+	s.analyzer, s.svg, s.svgErr = pa, svg, err
+	s.version++
+	version := s.version
+	for client := range s.clients {
+		select {
+		case client <- version:
+		default: // the client still has an update pending
+		}
+	}
+	s.mu.Unlock()
+}
+
+const livePage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Plugin Dependencies</title>
+<style>body { font-family: sans-serif; margin: 1em; } #status { color: #666; }</style>
+</head>
+<body>
+<p id="status">Live view, updates when composer.json files change.</p>
+<img id="graph" src="/graph.svg" alt="Plugin dependency graph">
+<script>
+var events = new EventSource("/events");
+events.onmessage = function (e) {
+  document.getElementById("graph").src = "/graph.svg?v=" + e.data;
+  document.getElementById("status").textContent = "Updated " + new Date().toLocaleTimeString();
+};
+</script>
+</body>
+</html>
+`
+
+func (s *graphServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/graph.svg", s.handleSVG)
+	mux.HandleFunc("/api/plugins", s.handlePlugins)
+	mux.HandleFunc("/events", s.handleEvents)
+	return mux
+}
+
+func (s *graphServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, livePage)
+}
+
+func (s *graphServer) handleSVG(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	svg, err := s.svg, s.svgErr
+	s.mu.RUnlock()
+
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate SVG: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(svg)
+}
+
+// handlePlugins serves the internal plugins in the -split-json document
+// format, as one array.
+func (s *graphServer) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	pa := s.analyzer
+	s.mu.RUnlock()
+
+	metrics := pa.Metrics()
+	details := []analyzer.PluginDetail{}
+	for _, name := range pa.InternalPluginNames() {
+		details = append(details, pa.PluginDetail(name, metrics))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(details)
+}
+
+func (s *graphServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	updates := make(chan int, 1)
+	s.mu.Lock()
+	s.clients[updates] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, updates)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case version := <-updates:
+			fmt.Fprintf(w, "data: %d\n\n", version)
+			flusher.Flush()
+		}
+	}
+}
+
+// runWatchServe serves the graph of pa on addr and rescans the plugins
+// whenever their composer.json or metadata files change. It only returns
+// if the server fails.
+func runWatchServe(pa *analyzer.PluginAnalyzer, addr string) error {
+	server := newGraphServer(pa)
+	go analyzer.WatchPlugins(pa, analyzer.WatchInterval, func(fresh *analyzer.PluginAnalyzer) {
+		log.Printf("Change detected, %d plugins rescanned", len(fresh.InternalPluginNames()))
+		server.update(fresh)
+	})
+
+	fmt.Printf("Serving live dependency graph at http://%s/\n", displayAddr(addr))
+	return http.ListenAndServe(addr, server.routes())
+}
+
+// rescanServer serves the HTML report with the Mermaid graph and the JSON
+// graph, scanning the plugins again for every request so that edits show
+// up on reload.
+type rescanServer struct {
+	base      *analyzer.PluginAnalyzer
+	customCSS string
+}
+
+func (s *rescanServer) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	mux.HandleFunc("/graph.json", s.handleJSON)
+	return mux
+}
+
+// scan rescans the plugins, answering with an error if that fails.
+func (s *rescanServer) scan(w http.ResponseWriter) (*analyzer.PluginAnalyzer, bool) {
+	fresh, err := s.base.Rescan()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to scan plugins: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return fresh, true
+}
+
+func (s *rescanServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	pa, ok := s.scan(w)
+	if !ok {
+		return
+	}
+	report, err := pa.GenerateHTML(s.customCSS)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, report)
+}
+
+func (s *rescanServer) handleJSON(w http.ResponseWriter, r *http.Request) {
+	pa, ok := s.scan(w)
+	if !ok {
+		return
+	}
+	data, err := pa.GenerateJSON()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to generate JSON: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// runServe serves the HTML report of pa's plugins directories on addr,
+// rescanning them on every request. It only returns if the server fails.
+func runServe(pa *analyzer.PluginAnalyzer, addr, customCSS string) error {
+	server := &rescanServer{base: pa, customCSS: customCSS}
+	fmt.Printf("Serving dependency graph at http://%s/ (JSON at /graph.json)\n", displayAddr(addr))
+	return http.ListenAndServe(addr, server.routes())
+}
+
+// displayAddr turns a listen address like ":8080" into one a browser can
+// open.
+func displayAddr(addr string) string {
+	if len(addr) > 0 && addr[0] == ':' {
+		return "localhost" + addr
+	}
+	return addr
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"time"
+)
+
+// phaseTiming is the measured duration of one named phase of a run.
+type phaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// phaseTimer records how long each phase of a run takes. A nil timer
+// records nothing, so call sites don't need to check whether -timing is set.
+type phaseTimer struct {
+	start  time.Time
+	phases []phaseTiming
+}
+
+func newPhaseTimer() *phaseTimer {
+	return &phaseTimer{start: time.Now()}
+}
+
+func noop() {}
+
+// track starts measuring the named phase and returns the function that
+// ends it.
+func (t *phaseTimer) track(name string) func() {
+	if t == nil {
+		return noop
+	}
+	begin := time.Now()
+	return func() {
+		t.phases = append(t.phases, phaseTiming{Name: name, Duration: time.Since(begin)})
+	}
+}
+
+// print writes the recorded phases in the order they ran, followed by the
+// total wall time since the timer was created, to stderr so -stdout output
+// stays clean.
+func (t *phaseTimer) print() {
+	if t == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "\nTiming:")
+	for _, p := range t.phases {
+		fmt.Fprintf(os.Stderr, "  %-20s %v\n", p.Name, p.Duration.Round(time.Microsecond))
+	}
+	fmt.Fprintf(os.Stderr, "  %-20s %v\n", "total", time.Since(t.start).Round(time.Microsecond))
+}
+
+// startCPUProfile writes a pprof CPU profile to path until the returned
+// function is called. It must be called before os.Exit, which skips
+// deferred calls.
+func startCPUProfile(path string) (func(), error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		f.Close()
+	}, nil
+}
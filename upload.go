@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// mermaidLiveTarget is the -upload value that builds a mermaid.live editor
+// link instead of posting the diagram anywhere.
+const mermaidLiveTarget = "mermaid.live"
+
+// uploadTokenEnv is read when -upload-token is not given, so tokens don't
+// have to appear in shell history.
+const uploadTokenEnv = "SW6_ANALYZER_UPLOAD_TOKEN"
+
+// mermaidLiveURL returns a mermaid.live editor link with the diagram encoded
+// in the URL, in the "pako:" format the editor uses for shared links.
+func mermaidLiveURL(diagram string) (string, error) {
+	state, err := json.Marshal(map[string]interface{}{
+		"code":    diagram,
+		"mermaid": `{"theme": "default"}`,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(state); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return "https://mermaid.live/edit#pako:" + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// uploadDiagram shares the Mermaid source and returns a URL to it. For
+// mermaidLiveTarget the link is built locally; any other target is treated
+// as a paste endpoint receiving the diagram as a text/plain POST, with the
+// token sent as a bearer token. The token is only sent over HTTPS, also
+// when following redirects, unless allowInsecure. The endpoint may answer
+// with the URL as plain text or as JSON with a "url" field.
+func uploadDiagram(target, token, diagram string, allowInsecure bool) (string, error) {
+	if target == mermaidLiveTarget {
+		return mermaidLiveURL(diagram)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target, strings.NewReader(diagram))
+	if err != nil {
+		return "", fmt.Errorf("invalid upload endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if token != "" {
+		if err := checkTokenScheme(req.URL, allowInsecure); err != nil {
+			return "", err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if token != "" {
+			return checkTokenScheme(req.URL, allowInsecure)
+		}
+		return nil
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("upload endpoint returned %s", resp.Status)
+	}
+
+	var parsed struct {
+		URL string `json:"url"`
+	}
+	if json.Unmarshal(body, &parsed) == nil && parsed.URL != "" {
+		return parsed.URL, nil
+	}
+	if url := strings.TrimSpace(string(body)); strings.HasPrefix(url, "http") {
+		return url, nil
+	}
+	if location := resp.Header.Get("Location"); location != "" {
+		return location, nil
+	}
+	return "", fmt.Errorf("upload endpoint did not return a URL")
+}
+
+// checkTokenScheme refuses to send the upload token to a URL that is not
+// HTTPS, where anyone on the way could read it, unless allowInsecure.
+func checkTokenScheme(u *url.URL, allowInsecure bool) error {
+	if u.Scheme == "https" || allowInsecure {
+		return nil
+	}
+	return fmt.Errorf("refusing to send the upload token to %s over %s; use an https endpoint or -upload-insecure", u.Host, u.Scheme)
+}
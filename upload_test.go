@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMermaidLiveURL(t *testing.T) {
+	link, err := mermaidLiveURL("graph TD\n  A --> B\n")
+	if err != nil {
+		t.Fatalf("mermaidLiveURL: %v", err)
+	}
+	encoded, ok := strings.CutPrefix(link, "https://mermaid.live/edit#pako:")
+	if !ok {
+		t.Fatalf("unexpected link %s", link)
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Code != "graph TD\n  A --> B\n" {
+		t.Errorf("code = %q", state.Code)
+	}
+}
+
+func TestUploadDiagramTokenNeedsHTTPS(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "" && r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		io.WriteString(w, "https://paste.example/abc\n")
+	}))
+	defer server.Close()
+
+	if _, err := uploadDiagram(server.URL, "secret", "graph TD", false); err == nil {
+		t.Error("token sent over http without -upload-insecure")
+	}
+	if requests != 0 {
+		t.Errorf("endpoint received %d requests, want 0", requests)
+	}
+
+	for _, tt := range []struct {
+		token    string
+		insecure bool
+	}{{"", false}, {"secret", true}} {
+		url, err := uploadDiagram(server.URL, tt.token, "graph TD", tt.insecure)
+		if err != nil || url != "https://paste.example/abc" {
+			t.Errorf("uploadDiagram(token %q, insecure %v) = %q, %v", tt.token, tt.insecure, url, err)
+		}
+	}
+}
+
+func TestUploadDiagramRefusesRedirectToHTTP(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("token followed the redirect to http")
+	}))
+	defer plain.Close()
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, plain.URL, http.StatusTemporaryRedirect)
+	}))
+	defer secure.Close()
+
+	// uploadDiagram uses its own client; trust the test server's certificate.
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = secure.Client().Transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	if _, err := uploadDiagram(secure.URL, "secret", "graph TD", false); err == nil {
+		t.Error("upload succeeded after a redirect to http")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/topdata-software-gmbh/sw6-plugin-analyzer/analyzer"
+)
+
+// withoutWatchFlag returns the arguments with -watch replaced by an explicit
+// -watch=false, so a run started with them generates the outputs once even
+// if the config file turns watch on.
+func withoutWatchFlag(args []string) []string {
+	out := []string{"-watch=false"}
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "watch" {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// regenerate runs the analyzer once with args, writing to our stdout and
+// stderr. Running it as a child process keeps every output exactly as in a
+// one-shot run. It returns the exit status of the run.
+func regenerate(args []string) int {
+	executable, err := os.Executable()
+	if err != nil {
+		log.Printf("Failed to regenerate: %v", err)
+		return exitFailure
+	}
+	cmd := exec.Command(executable, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		log.Printf("Failed to regenerate: %v", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// runWatch generates the outputs, then watches the plugins directories of pa
+// and generates them again after every change to a composer.json or
+// metadata file, until interrupted with Ctrl-C or SIGTERM. If the file
+// system can't notify about changes it falls back to polling.
+func runWatch(pa *analyzer.PluginAnalyzer) {
+	args := withoutWatchFlag(os.Args[1:])
+	regenerate(args)
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupted)
+	done := make(chan struct{})
+	go func() {
+		<-interrupted
+		close(done)
+	}()
+
+	onChange := func(*analyzer.PluginAnalyzer) {
+		if status := regenerate(args); status != exitOK {
+			log.Printf("Regenerated (exit status %d)", status)
+		} else {
+			log.Print("Regenerated")
+		}
+	}
+	log.Printf("Watching %s for changes, press Ctrl-C to stop", strings.Join(pa.PluginsDirs, ", "))
+	if err := analyzer.NotifyPluginsUntil(pa, analyzer.WatchInterval, done, onChange); err != nil {
+		log.Printf("Failed to watch for file changes, polling instead: %v", err)
+		analyzer.WatchPluginsUntil(pa, analyzer.WatchInterval, done, onChange)
+	}
+	log.Print("Stopped watching")
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithoutWatchFlagDisablesWatching(t *testing.T) {
+	got := withoutWatchFlag([]string{"-dir", "plugins", "-watch", "--watch=true", "-format=json"})
+	want := []string{"-watch=false", "-dir", "plugins", "-format=json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("withoutWatchFlag() = %q, want %q", got, want)
+	}
+}